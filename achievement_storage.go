@@ -0,0 +1,14 @@
+package rush
+
+// AchievementStorage 成就存储接口，与排行榜保存在同一位置。
+// Load 失败时游戏不再调用 Save，以免覆盖原来的存档
+type AchievementStorage interface {
+	Save(records []AchievementRecord) error
+	Load() ([]AchievementRecord, error)
+}
+
+// NewAchievementStorage 返回当前平台的成就存储实现
+func NewAchievementStorage() AchievementStorage {
+	// 具体实现由各平台的 build tag 文件提供
+	return newAchievementStorage()
+}
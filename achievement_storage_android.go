@@ -0,0 +1,23 @@
+//go:build android
+
+package rush
+
+import "path/filepath"
+
+type androidAchievementStorage struct {
+	filePath string
+}
+
+func newAchievementStorage() AchievementStorage {
+	return &androidAchievementStorage{
+		filePath: filepath.Join(androidDataDir(), "achievements.json"),
+	}
+}
+
+func (s *androidAchievementStorage) Save(records []AchievementRecord) error {
+	return saveJSONFile(s.filePath, records)
+}
+
+func (s *androidAchievementStorage) Load() ([]AchievementRecord, error) {
+	return loadJSONFile[[]AchievementRecord](s.filePath)
+}
@@ -0,0 +1,25 @@
+//go:build !android && !js
+
+package rush
+
+import "path/filepath"
+
+type desktopAchievementStorage struct{}
+
+func newAchievementStorage() AchievementStorage {
+	return &desktopAchievementStorage{}
+}
+
+// achievementFile 返回成就文件路径，位于存档目录中
+func (s *desktopAchievementStorage) achievementFile() string {
+	return filepath.Join(desktopDataDir(), "achievements.json")
+}
+
+func (s *desktopAchievementStorage) Save(records []AchievementRecord) error {
+	return saveJSONFile(s.achievementFile(), records)
+}
+
+func (s *desktopAchievementStorage) Load() ([]AchievementRecord, error) {
+	// 文件不存在时视为尚未解锁任何成就
+	return loadJSONFile[[]AchievementRecord](s.achievementFile())
+}
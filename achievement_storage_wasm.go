@@ -0,0 +1,36 @@
+//go:build js && wasm
+
+package rush
+
+import (
+	"encoding/json"
+	"syscall/js"
+)
+
+type wasmAchievementStorage struct{}
+
+func newAchievementStorage() AchievementStorage {
+	return &wasmAchievementStorage{}
+}
+
+const wasmAchievementKey = "rush_achievements"
+
+func (s *wasmAchievementStorage) Save(records []AchievementRecord) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	return localStorageSet(wasmAchievementKey, string(data))
+}
+
+func (s *wasmAchievementStorage) Load() ([]AchievementRecord, error) {
+	item := js.Global().Get("localStorage").Call("getItem", wasmAchievementKey)
+	if item.IsNull() || item.IsUndefined() {
+		return nil, nil
+	}
+	var loaded []AchievementRecord
+	if err := json.Unmarshal([]byte(item.String()), &loaded); err != nil {
+		return nil, err
+	}
+	return loaded, nil
+}
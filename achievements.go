@@ -0,0 +1,165 @@
+package rush
+
+import (
+	"fmt"
+	"time"
+
+	"rush/sim"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Achievement 成就定义，名字和说明需要放得下消息提示的宽度
+type Achievement struct {
+	ID       string                     // 存档中使用的标识，发布后不要修改
+	Name     string                     // 显示名
+	Desc     string                     // 解锁条件说明
+	Unlocked func(*achievementRun) bool // 本局是否满足解锁条件
+}
+
+// AchievementRecord 已解锁成就的存档记录
+type AchievementRecord struct {
+	ID   string `json:"id"`
+	Time int64  `json:"time"` // 解锁时间，Unix 秒
+}
+
+// achievementRun 判定成就用的本局状态，由模拟事件累计
+type achievementRun struct {
+	sim        *sim.Simulation
+	mode       sim.Mode
+	difficulty sim.Difficulty
+	standard   bool // 是否为标准对局，关卡、多条命等对局不能解锁胜利和距离类成就
+	nearMisses int  // 擦墙奖励次数
+	won        bool // 是否冲出隧道
+}
+
+// achievements 成就注册表，按列表界面的显示顺序排列
+var achievements = []Achievement{
+	{"escape", "Escaped", "Win a run", func(r *achievementRun) bool {
+		return r.standard && r.won
+	}},
+	{"pacifist", "Pacifist", "2000 no bombs", func(r *achievementRun) bool {
+		return r.standard && r.sim.Distance() >= 2000 && r.sim.BombsUsed() == 0
+	}},
+	{"coins50", "Hoarder", "50 coins a run", func(r *achievementRun) bool {
+		return r.sim.Coins() >= 50
+	}},
+	{"hard", "Hardcore", "Win on Hard", func(r *achievementRun) bool {
+		return r.standard && r.won && r.difficulty == sim.Hard
+	}},
+	{"combo", "Combo king", "Reach combo x5", func(r *achievementRun) bool {
+		return r.sim.Multiplier() >= 5
+	}},
+	{"daredevil", "Daredevil", "10 near misses", func(r *achievementRun) bool {
+		return r.nearMisses >= 10
+	}},
+	{"marathon", "Marathon", "Endless 5000", func(r *achievementRun) bool {
+		return r.standard && r.mode == sim.ModeEndless && r.sim.Distance() >= 5000
+	}},
+}
+
+// loadAchievements 读取已解锁的成就
+func (g *Game) loadAchievements() error {
+	loaded, err := g.achievementStorage.Load()
+	if err != nil {
+		return err
+	}
+	g.unlocked = make(map[string]int64, len(loaded))
+	for _, r := range loaded {
+		g.unlocked[r.ID] = r.Time
+	}
+	return nil
+}
+
+// saveAchievements 按注册表顺序保存已解锁的成就，读取失败过的存档不会被覆盖
+func (g *Game) saveAchievements() error {
+	if g.achievementsFailed {
+		return nil
+	}
+	var records []AchievementRecord
+	for _, a := range achievements {
+		if t, ok := g.unlocked[a.ID]; ok {
+			records = append(records, AchievementRecord{ID: a.ID, Time: t})
+		}
+	}
+	return g.achievementStorage.Save(records)
+}
+
+// startAchievementRun 开始新一局的成就判定
+func (g *Game) startAchievementRun() {
+	g.run = achievementRun{sim: g.sim, mode: g.mode, difficulty: g.difficulty, standard: g.isStandardRun()}
+}
+
+// trackAchievementEvent 根据模拟事件累计本局状态，播放回放时不累计
+func (g *Game) trackAchievementEvent(e sim.Event) {
+	if g.isPlayingBack() {
+		return
+	}
+	switch e.Kind {
+	case sim.EventNearMiss:
+		g.run.nearMisses++
+	case sim.EventWin:
+		g.run.won = true
+	}
+}
+
+// checkAchievements 解锁本局新满足条件的成就，播放回放和试玩编辑器关卡时不解锁
+func (g *Game) checkAchievements() {
+	if g.isPlayingBack() || g.isTestPlaying() || g.run.sim != g.sim {
+		return
+	}
+	changed := false
+	for _, a := range achievements {
+		if _, ok := g.unlocked[a.ID]; ok || !a.Unlocked(&g.run) {
+			continue
+		}
+		g.unlocked[a.ID] = time.Now().Unix()
+		g.toasts = append(g.toasts, "Got "+a.Name+"!")
+		changed = true
+	}
+	if changed {
+		_ = g.saveAchievements()
+	}
+}
+
+// updateToasts 上一条消息消失后显示下一条成就提示
+func (g *Game) updateToasts() {
+	if g.messageTimer > 0 || len(g.toasts) == 0 {
+		return
+	}
+	g.showMessage(g.toasts[0], 90)
+	g.toasts = g.toasts[1:]
+}
+
+// achievementMenuItems 返回成就列表，已解锁的带星号，选择时显示解锁条件
+func (g *Game) achievementMenuItems() []menuItem {
+	items := make([]menuItem, 0, len(achievements)+1)
+	for _, a := range achievements {
+		mark := "- "
+		if _, ok := g.unlocked[a.ID]; ok {
+			mark = "* "
+		}
+		items = append(items, menuItem{mark + a.Name, func() { g.showMessage(a.Desc, 90) }})
+	}
+	return append(items, menuItem{"Back", func() { g.state = StateMore }})
+}
+
+// updateAchievements 处理成就列表界面输入
+func (g *Game) updateAchievements() error {
+	if g.updateMenuList(g.achievementMenuItems(), &g.achievementChoice) {
+		g.state = StateMore
+	}
+	return nil
+}
+
+// drawAchievements 绘制成就列表，标题显示解锁进度
+func (g *Game) drawAchievements(screen *ebiten.Image) {
+	count := 0
+	for _, a := range achievements {
+		if _, ok := g.unlocked[a.ID]; ok {
+			count++
+		}
+	}
+	title := fmt.Sprintf("TROPHY %d/%d", count, len(achievements))
+	drawMenuList(screen, title, g.achievementMenuItems(), g.achievementChoice)
+}
@@ -1,8 +1,17 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
 	"log"
+	"os"
+	"path/filepath"
 	"rush"
+	"rush/sim"
+	"strings"
 
 	_ "github.com/ebitengine/hideconsole"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -14,9 +23,159 @@ const (
 )
 
 func main() {
+	seed := flag.Int64("seed", -1, "fixed random seed for every run (negative for random)")
+	replayFile := flag.String("replay", "", "play back a recorded replay file")
+	difficultyFile := flag.String("difficulty-file", "", "JSON file with the parameters of the Custom difficulty")
+	courseFile := flag.String("course", "", "play a course file instead of the random tunnel")
+	editFile := flag.String("edit", "", "open a course file in the editor, it is created on save if missing")
+	generator := flag.String("generator", "", "tunnel generator: "+strings.Join(sim.GeneratorNames(), ", "))
+	rectCollision := flag.Bool("rect-collision", false, "use the original rectangle collision instead of sprite masks")
+	items := flag.Bool("items", false, "spawn diamonds, fish and bomb refills besides coins")
+	bombCap := flag.Int("bomb-cap", sim.DefaultBombRules().Cap, "maximum number of bombs that can be held")
+	originalBombs := flag.Bool("original-bombs", false, "start with 3 bombs and never refill them like the original game")
+	obstacles := flag.Bool("obstacles", false, "spawn rocks, stalactites and mines")
+	enemies := flag.Bool("enemies", false, "spawn fish and jellyfish enemies")
+	combo := flag.Bool("combo", false, "score coin combos and near-miss bonuses")
+	powerups := flag.Bool("powerups", false, "spawn shields, time slows and magnets, a shield absorbs one hit")
+	lives := flag.Int("lives", 0, "number of lives, respawning at checkpoints (0 for the original single life)")
+	checkpoint := flag.Int("checkpoint", sim.DefaultLivesRules().Checkpoint, "distance between checkpoints with -lives")
+	blast := flag.Int("blast", sim.DefaultBlastRadius, "bomb blast radius, 0 clears the whole screen like the original game")
+	dataDir := flag.String("data-dir", "", "directory of high scores and other saves, overrides $"+rush.DataDirEnv+" (default: <user config dir>/rush)")
+	leaderboard := flag.Int("leaderboard", rush.DefaultLeaderboardSize, "number of high scores shown for each mode, all scores are kept")
+	flag.Parse()
+
+	// 回放、编辑器和关卡各自决定开局的内容，不能同时使用
+	exclusive := 0
+	for _, f := range []string{*replayFile, *editFile, *courseFile} {
+		if f != "" {
+			exclusive++
+		}
+	}
+	if exclusive > 1 {
+		fmt.Fprintln(flag.CommandLine.Output(), "only one of -replay, -edit and -course can be used")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *dataDir != "" {
+		rush.SetDataDir(*dataDir)
+	}
 	game := rush.NewGame()
+	if *seed >= 0 {
+		game.SetSeed(*seed)
+	}
+	if err := game.SetGenerator(*generator); err != nil {
+		log.Fatal(err)
+	}
+	if *rectCollision {
+		game.SetCollision(sim.CollisionRect)
+	}
+	if *items {
+		game.SetItems(true)
+	}
+	if *obstacles {
+		game.SetObstacles(true)
+	}
+	if *enemies {
+		game.SetEnemies(true)
+	}
+	if *combo {
+		game.SetScoring(true)
+	}
+	if *powerups {
+		game.SetPowerUps(true)
+	}
+	if *lives > 0 {
+		if err := game.SetLives(&sim.LivesRules{Count: *lives, Checkpoint: *checkpoint}); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := game.SetBlastRadius(*blast); err != nil {
+		log.Fatal(err)
+	}
+	if err := game.SetLeaderboardSize(*leaderboard); err != nil {
+		log.Fatal(err)
+	}
+	bombs := sim.DefaultBombRules()
+	bombs.Cap = *bombCap
+	bombRules := &bombs
+	if *originalBombs {
+		bombRules = nil
+	}
+	if err := game.SetBombRules(bombRules); err != nil {
+		log.Fatal(err)
+	}
+	if *difficultyFile != "" {
+		if err := loadCustomDifficulty(game, *difficultyFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *courseFile != "" {
+		course, err := rush.GetResourceManager().LoadCourseFile(*courseFile)
+		if err == nil {
+			err = game.PlayCourse(course)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *editFile != "" {
+		if err := editCourseFile(game, *editFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *replayFile != "" {
+		if err := playReplayFile(game, *replayFile); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	if err := ebiten.RunGame(game); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// playReplayFile 读取回放文件并开始播放
+func playReplayFile(game *rush.Game, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	r, err := sim.ReadReplay(file)
+	if err != nil {
+		return err
+	}
+	return game.PlayReplay(r)
+}
+
+// editCourseFile 在编辑器中打开关卡文件，保存时写回该文件
+func editCourseFile(game *rush.Game, path string) error {
+	course, err := rush.GetResourceManager().LoadCourseFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		course, err = sim.NewCourse(strings.TrimSuffix(filepath.Base(path), ".json")), nil
+	}
+	if err != nil {
+		return err
+	}
+	return game.EditCourse(course, func(c *sim.Course) error {
+		data, err := c.Marshal()
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0644)
+	})
+}
+
+// loadCustomDifficulty 从 JSON 文件读取自定义难度参数，未给出的参数沿用 Normal
+func loadCustomDifficulty(game *rush.Game, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	d := sim.Normal
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	return game.SetCustomDifficulty(d)
+}
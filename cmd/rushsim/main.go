@@ -0,0 +1,249 @@
+// rushsim 在无显示环境下批量运行模拟对局，用于 CI 和平衡性测试
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"rush/sim"
+	"strings"
+)
+
+// botInput 简单的机器人：跟随前方隧道的中线，前方有障碍物或敌人时使用炸弹
+func botInput(s *sim.Simulation) sim.Input {
+	snap := s.Snapshot()
+	p := snap.Player
+	target := -1.0
+	for _, t := range snap.Tunnels {
+		if t.X >= p.X+sim.PlayerWidth && t.X < p.X+sim.PlayerWidth+4 {
+			target = t.TopY + t.Height/2
+			break
+		}
+	}
+	if target < 0 {
+		target = sim.ScreenHeight / 2
+	}
+	center := p.Y + sim.PlayerHeight/2
+	return sim.Input{Up: center+p.VY*4 > target, Bomb: hazardAhead(&snap)}
+}
+
+// hazardAhead 检查玩家正前方不远处是否有障碍物或敌人
+func hazardAhead(snap *sim.Snapshot) bool {
+	p := snap.Player
+	ahead := func(x, y float64, h int) bool {
+		return x > p.X+sim.PlayerWidth && x < p.X+sim.PlayerWidth+botBombRange &&
+			y < p.Y+sim.PlayerHeight+2 && y+float64(h) > p.Y-2
+	}
+	for _, o := range snap.Obstacles {
+		if ahead(o.X, o.Y, o.H) {
+			return true
+		}
+	}
+	for _, e := range snap.Enemies {
+		if ahead(e.X, e.Y, e.H) {
+			return true
+		}
+	}
+	return false
+}
+
+// botBombRange 机器人在障碍物进入多远时使用炸弹
+const botBombRange = 30
+
+func main() {
+	games := flag.Int("n", 1000, "number of games to simulate")
+	seed := flag.Int64("seed", 0, "seed of the first game, following games use seed+1, seed+2, ...")
+	verbose := flag.Bool("v", false, "print the result of every game")
+	replayFile := flag.String("replay", "", "verify that a replay file reproduces its recorded result")
+	modeName := flag.String("mode", "classic", "game mode: classic or endless")
+	difficultyName := flag.String("difficulty", "normal", "difficulty preset: easy, normal or hard")
+	maxTicks := flag.Int("max-ticks", 1000000, "stop a game after this many ticks")
+	courseFile := flag.String("course", "", "play a course file instead of the random tunnel")
+	generator := flag.String("generator", sim.OriginalGenerator, "tunnel generator: "+strings.Join(sim.GeneratorNames(), ", "))
+	collisionName := flag.String("collision", "mask", "collision: mask or rect")
+	assetsDir := flag.String("assets", "assets/images", "directory of the sprites used for mask collision")
+	items := flag.Bool("items", false, "spawn diamonds, fish and bomb refills besides coins")
+	obstacles := flag.Bool("obstacles", false, "spawn rocks, stalactites and mines")
+	enemies := flag.Bool("enemies", false, "spawn fish and jellyfish enemies")
+	bombEconomy := flag.Bool("bombs", false, "use the default bomb refills, cap and unused bomb bonus")
+	bombCap := flag.Int("bomb-cap", sim.DefaultBombRules().Cap, "maximum number of bombs with -bombs")
+	blast := flag.Int("blast", 0, "launch bombs that explode with this radius instead of clearing the screen")
+	scoring := flag.Bool("scoring", false, "award coin combos and near-miss bonuses")
+	powerUps := flag.Bool("powerups", false, "spawn shields, time slows and magnets, a shield absorbs one hit")
+	lives := flag.Int("lives", 0, "number of lives, respawning at checkpoints (0 for the original single life)")
+	checkpoint := flag.Int("checkpoint", sim.DefaultLivesRules().Checkpoint, "distance between checkpoints with -lives")
+	flag.Parse()
+
+	if *replayFile != "" {
+		if err := verifyReplay(*replayFile); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	mode, err := sim.ParseMode(*modeName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	difficulty, err := sim.PresetByName(*difficultyName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rules := sim.DefaultRules()
+	rules.Mode = mode
+	rules.Difficulty = difficulty
+	rules.Generator = *generator
+	rules.Items = *items
+	rules.Obstacles = *obstacles
+	rules.Enemies = *enemies
+	rules.Blast = *blast
+	rules.Scoring = *scoring
+	rules.PowerUps = *powerUps
+	if *lives > 0 {
+		rules.Lives = &sim.LivesRules{Count: *lives, Checkpoint: *checkpoint}
+	}
+	if *bombEconomy {
+		bombs := sim.DefaultBombRules()
+		bombs.Cap = *bombCap
+		rules.Bombs = &bombs
+	}
+	if rules.Collision, err = sim.ParseCollision(*collisionName); err != nil {
+		log.Fatal(err)
+	}
+	if rules.Collision == sim.CollisionMask {
+		if rules.Masks, err = loadMasks(*assetsDir); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *courseFile != "" {
+		data, err := os.ReadFile(*courseFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if rules.Course, err = sim.ParseCourse(data); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := rules.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	wins, totalScore, totalDistance := 0, 0, 0
+	for i := 0; i < *games; i++ {
+		s := sim.New(*seed+int64(i), rules)
+		for tick := 0; tick < *maxTicks && s.Status() == sim.StatusRunning; tick++ {
+			s.Step(botInput(s))
+		}
+		if s.Status() == sim.StatusWon {
+			wins++
+		}
+		totalScore += s.Score()
+		totalDistance += s.Distance()
+		if *verbose {
+			b := s.Breakdown()
+			fmt.Printf("seed=%d status=%d score=%d distance=%d (distance=%d items=%d combo=%d near_miss=%d bomb=%d)\n",
+				s.Seed(), s.Status(), s.Score(), s.Distance(), b.Distance, b.Items, b.Combo, b.NearMiss, b.BombBonus)
+		}
+	}
+
+	if *games > 0 {
+		fmt.Printf("games=%d wins=%d avg_score=%.1f avg_distance=%.1f\n",
+			*games, wins, float64(totalScore)/float64(*games), float64(totalDistance)/float64(*games))
+	}
+}
+
+// loadMasks 由精灵图片生成逐像素碰撞的掩码
+func loadMasks(dir string) (*sim.Masks, error) {
+	player, err := loadMask(filepath.Join(dir, "submarine.png"))
+	if err != nil {
+		return nil, err
+	}
+	coin, err := loadMask(filepath.Join(dir, "coin.png"))
+	if err != nil {
+		return nil, err
+	}
+	masks := &sim.Masks{
+		Player:    player,
+		Coin:      coin,
+		Items:     make(map[sim.CollectibleKind]*sim.Mask),
+		Obstacles: make(map[sim.ObstacleKind]*sim.Mask),
+		Enemies:   make(map[sim.EnemyKind]*sim.Mask),
+	}
+	for kind, file := range itemSprites {
+		if masks.Items[kind], err = loadMask(filepath.Join(dir, file)); err != nil {
+			return nil, err
+		}
+	}
+	for kind, file := range obstacleSprites {
+		if masks.Obstacles[kind], err = loadMask(filepath.Join(dir, file)); err != nil {
+			return nil, err
+		}
+	}
+	for kind, file := range enemySprites {
+		if masks.Enemies[kind], err = loadMask(filepath.Join(dir, file)); err != nil {
+			return nil, err
+		}
+	}
+	return masks, nil
+}
+
+// obstacleSprites 各障碍物的精灵文件
+var obstacleSprites = map[sim.ObstacleKind]string{
+	sim.ObstacleRock:       "rock.png",
+	sim.ObstacleStalactite: "stalactite.png",
+	sim.ObstacleMine:       "mine.png",
+}
+
+// enemySprites 各敌人的精灵文件
+var enemySprites = map[sim.EnemyKind]string{
+	sim.EnemyFish:      "fish.png",
+	sim.EnemyJellyfish: "jellyfish.png",
+}
+
+// itemSprites 金币以外各道具的精灵文件
+var itemSprites = map[sim.CollectibleKind]string{
+	sim.KindDiamond:    "diamond.png",
+	sim.KindRedFish:    "redfish.png",
+	sim.KindBombRefill: "bomb.png",
+	sim.KindShield:     "shield.png",
+	sim.KindTimeSlow:   "timeslow.png",
+	sim.KindMagnet:     "magnet.png",
+}
+
+func loadMask(path string) (*sim.Mask, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	return sim.MaskFromImage(img), nil
+}
+
+// verifyReplay 无渲染播放回放，检查结果是否与录制时一致
+func verifyReplay(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	r, err := sim.ReadReplay(file)
+	if err != nil {
+		return err
+	}
+	s, err := r.Run()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("seed=%d frames=%d score=%d distance=%d\n", r.Seed, len(r.Frames), s.Score(), s.Distance())
+	if s.Score() != r.Score || s.Distance() != r.Distance {
+		return fmt.Errorf("replay desync: recorded score=%d distance=%d", r.Score, r.Distance)
+	}
+	return nil
+}
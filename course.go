@@ -0,0 +1,56 @@
+package rush
+
+import (
+	"log"
+
+	"rush/sim"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// PlayCourse 以指定关卡开始新游戏，先选择难度
+func (g *Game) PlayCourse(c *sim.Course) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	g.startNewGame(sim.ModeClassic)
+	g.course = c
+	return nil
+}
+
+// courseMenuItems 返回关卡选择菜单的选项
+func (g *Game) courseMenuItems() []menuItem {
+	var items []menuItem
+	for _, name := range GetResourceManager().CourseNames() {
+		items = append(items, menuItem{
+			label:  name,
+			action: func() { g.playBuiltinCourse(name) },
+		})
+	}
+	return append(items, menuItem{"Back", func() { g.state = StateMore }})
+}
+
+// playBuiltinCourse 加载内置关卡并开始游戏
+func (g *Game) playBuiltinCourse(name string) {
+	c, err := GetResourceManager().LoadCourse(name)
+	if err == nil {
+		err = g.PlayCourse(c)
+	}
+	if err != nil {
+		log.Printf("Failed to play course: %v", err)
+		g.showMessage("Bad course", 60)
+	}
+}
+
+// updateCourses 处理关卡选择界面输入
+func (g *Game) updateCourses() error {
+	if g.updateMenuList(g.courseMenuItems(), &g.courseChoice) {
+		g.state = StateMore
+	}
+	return nil
+}
+
+// drawCourses 绘制关卡选择界面
+func (g *Game) drawCourses(screen *ebiten.Image) {
+	drawMenuList(screen, "COURSES", g.courseMenuItems(), g.courseChoice)
+}
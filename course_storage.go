@@ -0,0 +1,22 @@
+package rush
+
+import (
+	"errors"
+
+	"rush/sim"
+)
+
+// ErrCourseNotFound 指定名字的关卡不存在
+var ErrCourseNotFound = errors.New("course not found")
+
+// CourseStorage 自制关卡存储接口，关卡与排行榜保存在同一位置
+type CourseStorage interface {
+	SaveCourse(c *sim.Course) error
+	LoadCourse(name string) (*sim.Course, error)
+}
+
+// NewCourseStorage 返回当前平台的关卡存储实现
+func NewCourseStorage() CourseStorage {
+	// 具体实现由各平台的 build tag 文件提供
+	return newCourseStorage()
+}
@@ -0,0 +1,44 @@
+//go:build android
+
+package rush
+
+import (
+	"os"
+	"path/filepath"
+
+	"rush/sim"
+)
+
+type androidCourseStorage struct {
+	dir string
+}
+
+func newCourseStorage() CourseStorage {
+	return &androidCourseStorage{
+		dir: androidDataDir(),
+	}
+}
+
+// courseFile 返回关卡文件路径，与排行榜文件位于同一目录
+func (s *androidCourseStorage) courseFile(name string) string {
+	return filepath.Join(s.dir, "course_"+name+".json")
+}
+
+func (s *androidCourseStorage) SaveCourse(c *sim.Course) error {
+	data, err := c.Marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.courseFile(c.Name), data, 0644)
+}
+
+func (s *androidCourseStorage) LoadCourse(name string) (*sim.Course, error) {
+	data, err := os.ReadFile(s.courseFile(name))
+	if os.IsNotExist(err) {
+		return nil, ErrCourseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sim.ParseCourse(data)
+}
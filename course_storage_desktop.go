@@ -0,0 +1,40 @@
+//go:build !android && !js
+
+package rush
+
+import (
+	"os"
+	"path/filepath"
+
+	"rush/sim"
+)
+
+type desktopCourseStorage struct{}
+
+func newCourseStorage() CourseStorage {
+	return &desktopCourseStorage{}
+}
+
+// courseFile 返回关卡文件路径，位于存档目录中
+func (s *desktopCourseStorage) courseFile(name string) string {
+	return filepath.Join(desktopDataDir(), "course_"+name+".json")
+}
+
+func (s *desktopCourseStorage) SaveCourse(c *sim.Course) error {
+	data, err := c.Marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.courseFile(c.Name), data, 0644)
+}
+
+func (s *desktopCourseStorage) LoadCourse(name string) (*sim.Course, error) {
+	data, err := os.ReadFile(s.courseFile(name))
+	if os.IsNotExist(err) {
+		return nil, ErrCourseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sim.ParseCourse(data)
+}
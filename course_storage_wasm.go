@@ -0,0 +1,34 @@
+//go:build js && wasm
+
+package rush
+
+import (
+	"syscall/js"
+
+	"rush/sim"
+)
+
+type wasmCourseStorage struct{}
+
+func newCourseStorage() CourseStorage {
+	return &wasmCourseStorage{}
+}
+
+const wasmCourseKeyPrefix = "rush_course_"
+
+func (s *wasmCourseStorage) SaveCourse(c *sim.Course) error {
+	data, err := c.Marshal()
+	if err != nil {
+		return err
+	}
+	js.Global().Get("localStorage").Call("setItem", wasmCourseKeyPrefix+c.Name, string(data))
+	return nil
+}
+
+func (s *wasmCourseStorage) LoadCourse(name string) (*sim.Course, error) {
+	item := js.Global().Get("localStorage").Call("getItem", wasmCourseKeyPrefix+name)
+	if item.IsNull() || item.IsUndefined() {
+		return nil, ErrCourseNotFound
+	}
+	return sim.ParseCourse([]byte(item.String()))
+}
@@ -0,0 +1,13 @@
+package rush
+
+// DataDirEnv 指定桌面版存档目录的环境变量
+const DataDirEnv = "RUSH_DATA_DIR"
+
+// customDataDir 由 SetDataDir 指定的存档目录
+var customDataDir string
+
+// SetDataDir 设置桌面版的存档目录，优先于 RUSH_DATA_DIR 环境变量，需在 NewGame 之前调用。
+// Android 使用 SetHighScoreDir，网页版存放在 localStorage 中，均不受影响。
+func SetDataDir(path string) {
+	customDataDir = path
+}
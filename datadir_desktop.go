@@ -0,0 +1,81 @@
+//go:build !android && !js
+
+package rush
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// preparedDataDir 已创建并迁移过旧存档的目录
+var preparedDataDir string
+
+// desktopDataDir 返回存档目录：SetDataDir 或 RUSH_DATA_DIR 指定的目录，
+// 否则为用户配置目录下的 rush 子目录。第一次使用时创建目录并迁移工作目录中的旧排行榜。
+func desktopDataDir() string {
+	dir := customDataDir
+	if dir == "" {
+		dir = os.Getenv(DataDirEnv)
+	}
+	if dir == "" {
+		if config, err := os.UserConfigDir(); err == nil {
+			dir = filepath.Join(config, "rush")
+		} else {
+			dir = "."
+		}
+	}
+	if dir != preparedDataDir {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			log.Printf("Failed to create data directory: %v", err)
+		}
+		migrateLegacyData(dir)
+		preparedDataDir = dir
+	}
+	return dir
+}
+
+// legacyDataFiles 旧版本保存在工作目录中、需要迁移的存档。
+// 其他存档的文件名过于通用，可能属于别的程序，不做迁移。
+var legacyDataFiles = []string{
+	desktopHighScoreFile,
+	backupPath(desktopHighScoreFile),
+}
+
+// migrateLegacyData 把旧版本留在工作目录中的排行榜移动到 dir，dir 中已有同名文件时保留旧文件不动
+func migrateLegacyData(dir string) {
+	wd, err := filepath.Abs(".")
+	if err != nil {
+		return
+	}
+	if abs, err := filepath.Abs(dir); err != nil || abs == wd {
+		return
+	}
+	for _, legacy := range legacyDataFiles {
+		if _, err := os.Stat(legacy); err != nil {
+			continue
+		}
+		target := filepath.Join(dir, legacy)
+		if _, err := os.Stat(target); !os.IsNotExist(err) {
+			continue
+		}
+		if err := moveFile(legacy, target); err != nil {
+			log.Printf("Failed to migrate %s: %v", legacy, err)
+		}
+	}
+}
+
+// moveFile 移动文件，跨文件系统时复制后删除原文件
+func moveFile(src, dst string) error {
+	if os.Rename(src, dst) == nil {
+		return nil
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(dst, data); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
@@ -0,0 +1,82 @@
+package rush
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"rush/sim"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// SetCustomDifficulty 设置难度选择界面中"Custom"对应的参数
+func (g *Game) SetCustomDifficulty(d sim.Difficulty) error {
+	d.Name = sim.CustomDifficultyName
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	g.customDifficulty = d
+	return nil
+}
+
+// difficultyOptions 返回可选择的难度，最后一项为自定义难度
+func (g *Game) difficultyOptions() []sim.Difficulty {
+	return append(sim.Presets(), g.customDifficulty)
+}
+
+// difficultyMenuItems 返回难度选择菜单的选项
+func (g *Game) difficultyMenuItems() []menuItem {
+	var items []menuItem
+	for _, d := range g.difficultyOptions() {
+		items = append(items, menuItem{
+			label:  difficultyLabel(d.Name),
+			action: func() { g.chooseDifficulty(d) },
+		})
+	}
+	return items
+}
+
+// chooseDifficulty 确定难度后进入该模式的排行榜，之后开始倒计时；不计排名的对局直接倒计时
+func (g *Game) chooseDifficulty(d sim.Difficulty) {
+	g.difficulty = d
+	g.reset()
+	if !g.isRankedRun() {
+		g.state = StateCountdown
+		return
+	}
+	g.openHighScores(StateHighScoresThenGame)
+}
+
+// updateDifficulty 处理难度选择界面输入
+func (g *Game) updateDifficulty() error {
+	if g.updateMenuList(g.difficultyMenuItems(), &g.difficultyChoice) {
+		g.state = StateTitle
+	}
+	return nil
+}
+
+// drawDifficulty 绘制难度选择界面及所选难度的参数
+func (g *Game) drawDifficulty(screen *ebiten.Image) {
+	drawMenuList(screen, "DIFFICULTY", g.difficultyMenuItems(), g.difficultyChoice)
+
+	d := g.difficultyOptions()[g.difficultyChoice]
+	info := fmt.Sprintf("Min %d\nNarr%d\nG %.2f", int(d.MinTunnelHeight), d.NarrowInterval, d.Gravity)
+	drawHandDrawnText(screen, info, 88, menuListTop+1, color.RGBA{128, 128, 128, 255})
+}
+
+// difficultyLabel 返回难度名的显示文字，如 "normal" 显示为 "Normal"
+func difficultyLabel(name string) string {
+	if name == "" {
+		return ""
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// difficultyMark 返回排行榜上表示难度的单个字母
+func difficultyMark(name string) string {
+	if name == "" {
+		name = sim.Normal.Name
+	}
+	return strings.ToUpper(name[:1])
+}
@@ -0,0 +1,377 @@
+package rush
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"math"
+	"slices"
+
+	"rush/sim"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	editorPlayerX     = screenWidth / 4 // 潜艇在屏幕上的X坐标，与游戏中一致
+	editorScrollSpeed = 2               // 每帧滚动的距离
+	editorGrabRadius  = 3               // 点击控制点的判定半径
+	editorCourseName  = "my_course"     // 编辑器默认保存的关卡名
+)
+
+// 编辑器顶部工具栏按钮
+var (
+	editorLeftRect  = image.Rect(0, 0, 10, 10)
+	editorModeRect  = image.Rect(12, 0, 30, 10)
+	editorDelRect   = image.Rect(32, 0, 58, 10)
+	editorEndRect   = image.Rect(60, 0, 86, 10)
+	editorPlayRect  = image.Rect(88, 0, 106, 10)
+	editorSaveRect  = image.Rect(108, 0, 142, 10)
+	editorRightRect = image.Rect(150, 0, 160, 10)
+)
+
+// editorEdge 正在拖动的控制点
+type editorEdge int
+
+const (
+	edgeNone   editorEdge = iota
+	edgeTop               // 隧道顶部
+	edgeBottom            // 隧道底部
+)
+
+// courseEditor 关卡编辑器状态
+type courseEditor struct {
+	course     *sim.Course
+	save       func(*sim.Course) error
+	view       int         // 潜艇所在位置对应的关卡距离，试玩从这里开始
+	coinMode   bool        // true 时点击放置或删除金币，否则编辑隧道
+	selected   int         // 选中的关键帧，-1 表示无
+	drag       editorEdge  // 正在拖动的控制点
+	testCourse *sim.Course // 正在试玩的关卡
+}
+
+// EditCourse 打开关卡编辑器编辑关卡的副本，保存时调用 save
+func (g *Game) EditCourse(c *sim.Course, save func(*sim.Course) error) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	g.editor = &courseEditor{
+		course:   c.Clone(),
+		save:     save,
+		selected: -1,
+	}
+	g.ghost = nil
+	g.state = StateEditor
+	return nil
+}
+
+// openEditor 从菜单进入编辑器，优先继续上次的编辑，其次读取已保存的自制关卡
+func (g *Game) openEditor() {
+	if g.editor != nil {
+		g.ghost = nil
+		g.state = StateEditor
+		return
+	}
+	c, err := g.courseStorage.LoadCourse(editorCourseName)
+	if err != nil {
+		if !errors.Is(err, ErrCourseNotFound) {
+			log.Printf("Failed to load course: %v", err)
+		}
+		c = sim.NewCourse(editorCourseName)
+	}
+	if err := g.EditCourse(c, g.courseStorage.SaveCourse); err != nil {
+		log.Printf("Failed to open editor: %v", err)
+	}
+}
+
+// isTestPlaying 检查当前是否在试玩编辑器中的关卡
+func (g *Game) isTestPlaying() bool {
+	return g.editor != nil && g.course != nil && g.course == g.editor.testCourse
+}
+
+// afterRunState 返回一局结束后要进入的界面，编辑器试玩结束后回到编辑器
+func (g *Game) afterRunState() GameState {
+	if g.isTestPlaying() {
+		return StateEditor
+	}
+	return StateTitle
+}
+
+// screenX 返回关卡距离在编辑器屏幕上的X坐标，与游戏中关卡物体出现的位置一致
+func (e *courseEditor) screenX(distance int) int {
+	return sim.CourseOriginX + distance - e.view
+}
+
+// distanceAt 返回编辑器屏幕X坐标对应的关卡距离
+func (e *courseEditor) distanceAt(x int) int {
+	return e.view + x - sim.CourseOriginX
+}
+
+// maxView 返回可以滚动到的最远距离
+func (e *courseEditor) maxView() int {
+	last := e.course.Keyframes[len(e.course.Keyframes)-1].Distance
+	return max(last, e.course.WinDistance) + screenWidth
+}
+
+// updateEditor 处理编辑器输入
+func (g *Game) updateEditor() error {
+	e := g.editor
+	if g.in.IsPressed(ActionBack) {
+		e.drag = edgeNone
+		g.state = StateMore
+		return nil
+	}
+
+	if g.in.IsHeld(ActionLeft) || g.in.PointerIn(editorLeftRect) {
+		e.view = max(e.view-editorScrollSpeed, 0)
+	}
+	if g.in.IsHeld(ActionRight) || g.in.PointerIn(editorRightRect) {
+		e.view = min(e.view+editorScrollSpeed, e.maxView())
+	}
+
+	switch {
+	case g.in.IsPressed(ActionBomb) || g.in.TapIn(editorModeRect):
+		e.coinMode = !e.coinMode
+		e.selected = -1
+	case g.in.IsPressed(ActionErase) || g.in.TapIn(editorDelRect):
+		e.deleteSelected()
+	case g.in.TapIn(editorEndRect):
+		e.course.WinDistance = max(e.view, 1)
+	case g.in.IsPressed(ActionConfirm) || g.in.TapIn(editorPlayRect):
+		g.testPlayCourse()
+		return nil
+	case g.in.IsPressed(ActionEnd) || g.in.TapIn(editorSaveRect):
+		g.saveEditedCourse()
+	}
+
+	if e.drag != edgeNone {
+		if len(g.in.Pointers) == 0 {
+			e.drag = edgeNone
+		} else {
+			e.moveSelected(g.in.Pointers[0].Y)
+		}
+	}
+
+	for _, p := range g.in.Taps {
+		if p.Y < editorLeftRect.Max.Y {
+			continue // 工具栏
+		}
+		if e.coinMode {
+			e.toggleCoin(p.Point)
+		} else {
+			e.grabOrInsert(p.Point)
+		}
+	}
+	return nil
+}
+
+// grabOrInsert 抓取点击位置的控制点，没有时在该距离插入新的关键帧
+func (e *courseEditor) grabOrInsert(p image.Point) {
+	ks := e.course.Keyframes
+	for i, k := range ks {
+		if abs(p.X-e.screenX(k.Distance)) > editorGrabRadius {
+			continue
+		}
+		if abs(p.Y-int(k.TopY)) <= editorGrabRadius {
+			e.selected, e.drag = i, edgeTop
+			return
+		}
+		if abs(p.Y-int(k.TopY+k.Height)) <= editorGrabRadius {
+			e.selected, e.drag = i, edgeBottom
+			return
+		}
+	}
+
+	d := e.distanceAt(p.X)
+	i, found := slices.BinarySearchFunc(ks, d, func(k sim.Keyframe, d int) int { return k.Distance - d })
+	if !found {
+		top, h := e.course.ProfileAt(d)
+		e.course.Keyframes = slices.Insert(ks, i, sim.Keyframe{Distance: d, TopY: top, Height: h})
+	}
+	k := e.course.Keyframes[i]
+	e.selected, e.drag = i, edgeBottom
+	if float64(p.Y) < k.TopY+k.Height/2 {
+		e.drag = edgeTop
+	}
+	e.moveSelected(p.Y)
+}
+
+// moveSelected 把选中关键帧的顶部或底部移动到指定Y坐标，另一侧保持不动
+func (e *courseEditor) moveSelected(y int) {
+	if e.selected < 0 {
+		return
+	}
+	k := &e.course.Keyframes[e.selected]
+	top, bottom := k.TopY, k.TopY+k.Height
+	switch e.drag {
+	case edgeTop:
+		top = math.Max(0, math.Min(float64(y), bottom-sim.MinCourseHeight))
+	case edgeBottom:
+		bottom = math.Min(screenHeight, math.Max(float64(y), top+sim.MinCourseHeight))
+	}
+	k.TopY, k.Height = top, bottom-top
+}
+
+// deleteSelected 删除选中的关键帧，至少保留一个
+func (e *courseEditor) deleteSelected() {
+	if e.selected < 0 || len(e.course.Keyframes) <= 1 {
+		return
+	}
+	e.course.Keyframes = slices.Delete(e.course.Keyframes, e.selected, e.selected+1)
+	e.selected = -1
+	e.drag = edgeNone
+}
+
+// toggleCoin 删除点击位置的金币，没有时在该位置放置金币
+func (e *courseEditor) toggleCoin(p image.Point) {
+	for i, coin := range e.course.Coins {
+		x, spec := e.screenX(coin.Distance), coin.Kind.Spec()
+		r := image.Rect(x, int(coin.Y), x+spec.W, int(coin.Y)+spec.H).Inset(-2)
+		if p.In(r) {
+			e.course.Coins = slices.Delete(e.course.Coins, i, i+1)
+			return
+		}
+	}
+	y := min(max(p.Y-sim.CoinHeight/2, 0), screenHeight-sim.CoinHeight)
+	e.course.Coins = append(e.course.Coins, sim.CoinSpawn{
+		Distance: e.distanceAt(p.X - sim.CoinWidth/2),
+		Y:        float64(y),
+	})
+	e.course.Normalize()
+}
+
+// testPlayCourse 从编辑器当前位置试玩，试玩不录制回放也不计入排行榜
+func (g *Game) testPlayCourse() {
+	e := g.editor
+	e.drag = edgeNone
+	e.testCourse = e.course.From(e.view)
+	if err := e.testCourse.Validate(); err != nil {
+		g.showMessage("Bad course", 60)
+		return
+	}
+	g.mode = sim.ModeClassic
+	g.course = e.testCourse
+	g.reset()
+	g.recording = nil
+	g.ghost = nil
+	g.state = StateCountdown
+}
+
+// saveEditedCourse 保存正在编辑的关卡
+func (g *Game) saveEditedCourse() {
+	err := g.editor.course.Validate()
+	if err == nil {
+		err = g.editor.save(g.editor.course)
+	}
+	if err != nil {
+		log.Printf("Failed to save course: %v", err)
+		g.showMessage("Save failed", 60)
+		return
+	}
+	g.showMessage("Saved", 60)
+}
+
+// editorSnapshot 把编辑器可见范围内的关卡转换为游戏画面快照，保证所见即所玩
+func (e *courseEditor) editorSnapshot() *sim.Snapshot {
+	snap := &sim.Snapshot{}
+	for x := 0; x < screenWidth; x++ {
+		top, h := e.course.ProfileAt(e.distanceAt(x))
+		snap.Tunnels = append(snap.Tunnels, sim.Tunnel{X: float64(x), TopY: top, Height: h, Width: 1})
+	}
+	for _, coin := range e.course.Coins {
+		x, spec := e.screenX(coin.Distance), coin.Kind.Spec()
+		if x+spec.W < 0 || x >= screenWidth {
+			continue
+		}
+		snap.Collectibles = append(snap.Collectibles, sim.Collectible{
+			X:    float64(x),
+			Y:    coin.Y,
+			W:    spec.W,
+			H:    spec.H,
+			Kind: coin.Kind,
+		})
+	}
+	for _, o := range e.course.Obstacles {
+		x, spec := e.screenX(o.Distance), o.Kind.Spec()
+		if x+spec.W < 0 || x >= screenWidth {
+			continue
+		}
+		snap.Obstacles = append(snap.Obstacles, sim.Obstacle{
+			X:    float64(x),
+			Y:    o.Y,
+			W:    spec.W,
+			H:    spec.H,
+			Kind: o.Kind,
+		})
+	}
+	for _, en := range e.course.Enemies {
+		x, spec := e.screenX(en.Distance), en.Kind.Spec()
+		if x+spec.W < 0 || x >= screenWidth {
+			continue
+		}
+		snap.Enemies = append(snap.Enemies, sim.Enemy{
+			X:       float64(x),
+			Y:       en.Y,
+			W:       spec.W,
+			H:       spec.H,
+			Kind:    en.Kind,
+			Pattern: en.Pattern,
+		})
+	}
+	top, h := e.course.ProfileAt(e.view)
+	snap.Player = sim.Player{X: editorPlayerX, Y: top + h/2}
+	return snap
+}
+
+// drawEditor 绘制编辑器
+func (g *Game) drawEditor(screen *ebiten.Image) {
+	e := g.editor
+	g.drawGameScene(screen, e.editorSnapshot())
+
+	// 终点线
+	if x := e.screenX(e.course.WinDistance); x >= 0 && x < screenWidth {
+		vector.StrokeLine(screen, float32(x), 0, float32(x), screenHeight, 1, color.RGBA{255, 0, 0, 255}, false)
+	}
+
+	// 关键帧控制点
+	if !e.coinMode {
+		for i, k := range e.course.Keyframes {
+			x := e.screenX(k.Distance)
+			if x < -editorGrabRadius || x > screenWidth+editorGrabRadius {
+				continue
+			}
+			clr := color.RGBA{255, 255, 0, 255}
+			if i == e.selected {
+				clr = color.RGBA{255, 0, 0, 255}
+			}
+			for _, y := range []float64{k.TopY, k.TopY + k.Height} {
+				vector.DrawFilledRect(screen, float32(x-1), float32(y-1), 3, 3, clr, false)
+			}
+		}
+	}
+
+	// 工具栏
+	mode := "PT"
+	if e.coinMode {
+		mode = "CN"
+	}
+	drawTextButton(screen, editorLeftRect, "<")
+	drawTextButton(screen, editorModeRect, mode)
+	drawTextButton(screen, editorDelRect, "DEL")
+	drawTextButton(screen, editorEndRect, "END")
+	drawTextButton(screen, editorPlayRect, "GO")
+	drawTextButton(screen, editorSaveRect, "SAVE")
+	drawTextButton(screen, editorRightRect, ">")
+
+	drawHandDrawnText(screen, fmt.Sprintf("%d", e.view), 2, screenHeight-9, color.White)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,33 @@
+package rush
+
+import (
+	"slices"
+
+	"rush/sim"
+)
+
+// SetGenerator 选择之后各局使用的隧道生成器，空字符串为原版
+func (g *Game) SetGenerator(name string) error {
+	rules := sim.DefaultRules()
+	rules.Generator = name
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+	g.generator = name
+	return nil
+}
+
+// generatorName 返回当前隧道生成器的名字
+func (g *Game) generatorName() string {
+	if g.generator == "" {
+		return sim.OriginalGenerator
+	}
+	return g.generator
+}
+
+// cycleGenerator 切换到下一个已注册的隧道生成器
+func (g *Game) cycleGenerator() {
+	names := sim.GeneratorNames()
+	i := slices.Index(names, g.generatorName())
+	g.generator = names[(i+1)%len(names)]
+}
@@ -0,0 +1,155 @@
+package rush
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"log"
+	"sort"
+	"strings"
+
+	"rush/sim"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	bestReplayPrefix = "best_" // 最佳回放名字的前缀
+	maxBestReplays   = 20      // 最多保留的最佳回放数量，超出时删除分数最低的
+)
+
+// bestReplayName 返回指定规则和种子最佳回放的名字，规则由其哈希区分
+func bestReplayName(rules sim.Rules, seed int64) string {
+	return fmt.Sprintf("%s%s_%d", bestReplayPrefix, rules.Hash(), seed)
+}
+
+// ghost 沿着同种子最佳回放行进的幽灵潜艇
+type ghost struct {
+	replay *sim.Replay
+	sim    *sim.Simulation
+	frame  int
+}
+
+// newGhost 根据回放创建幽灵
+func newGhost(r *sim.Replay) *ghost {
+	return &ghost{
+		replay: r,
+		sim:    r.NewSimulation(),
+	}
+}
+
+// step 按回放推进幽灵一帧
+func (gh *ghost) step() {
+	if gh.frame >= len(gh.replay.Frames) {
+		return
+	}
+	gh.sim.Step(gh.replay.Frames[gh.frame])
+	gh.frame++
+}
+
+// visible 检查幽灵是否仍在隧道中
+func (gh *ghost) visible() bool {
+	return gh.sim.Status() == sim.StatusRunning && gh.frame < len(gh.replay.Frames)
+}
+
+// loadGhost 加载当前种子的最佳回放作为幽灵，没有时不显示幽灵
+func (g *Game) loadGhost() {
+	g.ghost = nil
+	r, err := g.replayStorage.LoadReplay(bestReplayName(g.rules(), g.seed))
+	if err != nil {
+		if !errors.Is(err, ErrReplayNotFound) {
+			log.Printf("Failed to load best replay: %v", err)
+		}
+		return
+	}
+	if err := r.Check(); err != nil {
+		log.Printf("Ignoring best replay: %v", err)
+		return
+	}
+	if !r.Rules.Equal(g.rules()) {
+		// 自定义难度参数改变后旧回放不再对应同一条隧道
+		return
+	}
+	g.ghost = newGhost(r)
+}
+
+// saveBestReplay 分数超过同种子的最佳回放时保存为新的最佳回放
+func (g *Game) saveBestReplay(r *sim.Replay) {
+	name := bestReplayName(r.Rules, r.Seed)
+	best, err := g.replayStorage.LoadReplay(name)
+	if err == nil && best.Check() == nil && best.Rules.Equal(r.Rules) && best.Score >= r.Score {
+		return
+	}
+	if err := g.replayStorage.SaveReplay(name, r); err != nil {
+		// 存储空间不足时删掉一半的最佳回放再试一次
+		g.pruneBestReplays(maxBestReplays / 2)
+		if err := g.replayStorage.SaveReplay(name, r); err != nil {
+			log.Printf("Failed to save best replay: %v", err)
+			return
+		}
+	}
+	g.pruneBestReplays(maxBestReplays)
+}
+
+// pruneBestReplays 只保留分数最高的 keep 个最佳回放，读不出的回放最先删除
+func (g *Game) pruneBestReplays(keep int) {
+	names, err := g.replayStorage.ListReplays()
+	if err != nil {
+		log.Printf("Failed to list replays: %v", err)
+		return
+	}
+	type entry struct {
+		name  string
+		score int
+	}
+	var best []entry
+	for _, name := range names {
+		if !strings.HasPrefix(name, bestReplayPrefix) {
+			continue
+		}
+		score := -1
+		if r, err := g.replayStorage.LoadReplay(name); err == nil {
+			score = r.Score
+		}
+		best = append(best, entry{name, score})
+	}
+	if len(best) <= keep {
+		return
+	}
+	sort.Slice(best, func(i, j int) bool { return best[i].score > best[j].score })
+	for _, e := range best[keep:] {
+		if err := g.replayStorage.DeleteReplay(e.name); err != nil {
+			log.Printf("Failed to delete replay %s: %v", e.name, err)
+		}
+	}
+}
+
+// drawGhost 绘制半透明的幽灵潜艇
+func (g *Game) drawGhost(screen *ebiten.Image) {
+	if g.ghost == nil || !g.ghost.visible() {
+		return
+	}
+	submarineImage := GetResourceManager().GetResource(ResourceSubmarine)
+	if submarineImage == nil {
+		return
+	}
+	p := g.ghost.sim.Player()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(p.X, p.Y)
+	op.ColorScale.ScaleAlpha(0.4)
+	screen.DrawImage(submarineImage, op)
+}
+
+// drawGhostIndicator 在 HUD 上显示领先或落后幽灵的分数
+func (g *Game) drawGhostIndicator(screen *ebiten.Image, snap *sim.Snapshot) {
+	if g.ghost == nil {
+		return
+	}
+	diff := snap.Score - g.ghost.sim.Score()
+	text := fmt.Sprintf("%+d", diff)
+	clr := color.RGBA{0, 255, 0, 255}
+	if diff < 0 {
+		clr = color.RGBA{255, 64, 64, 255}
+	}
+	drawHandDrawnText(screen, text, 5, 15, clr)
+}
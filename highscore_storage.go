@@ -1,12 +1,98 @@
 package rush
 
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"rush/sim"
+)
+
+// HighScoreStorage 排行榜存储接口。
+// 存档损坏而从备份恢复时，Load 同时返回恢复的记录和 ErrHighScoresRecovered。
 type HighScoreStorage interface {
 	Save(highScores []HighScore) error
 	Load() ([]HighScore, error)
 }
 
+var (
+	// ErrHighScoresRecovered 排行榜存档损坏，已从上一次的备份恢复
+	ErrHighScoresRecovered = errors.New("high scores recovered from backup")
+	// ErrHighScoreChecksum 排行榜存档的校验和不符，文件已损坏
+	ErrHighScoreChecksum = errors.New("high score file checksum mismatch")
+)
+
 // NewHighScoreStorage 返回当前平台的高分存储实现
 func NewHighScoreStorage() HighScoreStorage {
 	// 具体实现由各平台的 build tag 文件提供
 	return newHighScoreStorage()
 }
+
+// highScoreFileVersion 排行榜文件格式版本。
+// 版本 1 是不带版本号的记录数组，每种模式固定 5 行，空位为没有名字的记录；
+// 版本 2 没有校验和。
+const highScoreFileVersion = 3
+
+// highScoreFile 排行榜文件的 JSON 结构
+type highScoreFile struct {
+	Version  int             `json:"version"`
+	Checksum string          `json:"checksum,omitempty"` // scores 原始 JSON 的 SHA-256
+	Scores   json.RawMessage `json:"scores"`
+}
+
+// highScoreChecksum 计算记录 JSON 的校验和
+func highScoreChecksum(scores []byte) string {
+	sum := sha256.Sum256(scores)
+	return hex.EncodeToString(sum[:])
+}
+
+// marshalHighScores 以当前格式编码排行榜
+func marshalHighScores(highScores []HighScore) ([]byte, error) {
+	scores, err := json.Marshal(highScores)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(highScoreFile{Version: highScoreFileVersion, Checksum: highScoreChecksum(scores), Scores: scores})
+}
+
+// unmarshalHighScores 解码排行榜文件，旧格式的记录会被迁移到当前格式，
+// 此时 migrated 为 true，调用方应以当前格式写回
+func unmarshalHighScores(data []byte) (highScores []HighScore, migrated bool, err error) {
+	var file highScoreFile
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		file = highScoreFile{Version: 1, Scores: data}
+	} else if err := json.Unmarshal(data, &file); err != nil {
+		return nil, false, err
+	}
+	if file.Version > highScoreFileVersion {
+		return nil, false, fmt.Errorf("unsupported high score file version: %d", file.Version)
+	}
+	if file.Version >= 3 && file.Checksum != highScoreChecksum(file.Scores) {
+		return nil, false, ErrHighScoreChecksum
+	}
+	if err := json.Unmarshal(file.Scores, &highScores); err != nil {
+		return nil, false, err
+	}
+	if file.Version == 1 {
+		highScores = migrateHighScores(highScores)
+	}
+	return highScores, file.Version < highScoreFileVersion, nil
+}
+
+// migrateHighScores 把版本 1 的记录升级到当前格式：去掉空位，补上当时唯一的难度
+func migrateHighScores(old []HighScore) []HighScore {
+	var migrated []HighScore
+	for _, hs := range old {
+		if hs.Name == "" {
+			continue
+		}
+		if hs.Difficulty == "" {
+			hs.Difficulty = sim.Normal.Name
+		}
+		migrated = append(migrated, hs)
+	}
+	return migrated
+}
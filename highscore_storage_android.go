@@ -3,7 +3,6 @@
 package rush
 
 import (
-	"encoding/json"
 	"os"
 	"path/filepath"
 )
@@ -19,8 +18,8 @@ func SetHighScoreDir(path string) {
 	customHighScoreDir = path
 }
 
-func newHighScoreStorage() HighScoreStorage {
-	// 优先使用 Java 层传递的目录
+// androidDataDir 返回存档目录，优先使用 Java 层传递的目录
+func androidDataDir() string {
 	dir := customHighScoreDir
 	if dir == "" {
 		var err error
@@ -29,34 +28,19 @@ func newHighScoreStorage() HighScoreStorage {
 			dir = "."
 		}
 	}
+	return dir
+}
+
+func newHighScoreStorage() HighScoreStorage {
 	return &androidHighScoreStorage{
-		filePath: filepath.Join(dir, "highscores.json"),
+		filePath: filepath.Join(androidDataDir(), "highscores.json"),
 	}
 }
 
 func (s *androidHighScoreStorage) Save(highScores []HighScore) error {
-	file, err := os.Create(s.filePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-	enc := json.NewEncoder(file)
-	return enc.Encode(highScores)
+	return saveHighScoreFile(s.filePath, highScores)
 }
 
 func (s *androidHighScoreStorage) Load() ([]HighScore, error) {
-	file, err := os.Open(s.filePath)
-	if err != nil {
-		return make([]HighScore, 5), nil
-	}
-	defer file.Close()
-	dec := json.NewDecoder(file)
-	var loaded []HighScore
-	if err := dec.Decode(&loaded); err != nil {
-		return nil, err
-	}
-	for len(loaded) < 5 {
-		loaded = append(loaded, HighScore{"", 0})
-	}
-	return loaded[:5], nil
+	return loadHighScoreFile(s.filePath)
 }
@@ -0,0 +1,76 @@
+package rush
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"rush/sim"
+)
+
+func TestUnmarshalHighScores(t *testing.T) {
+	zero := 0
+	current, err := marshalHighScores([]HighScore{{Name: "CAT", Score: 500, Mode: sim.ModeEndless, Difficulty: "easy", BombsUsed: &zero}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		data     string
+		want     []HighScore
+		migrated bool
+		err      error
+	}{
+		{
+			name: "v1",
+			data: `[{"name":"AAA","score":300},{"name":"BBB","score":120},{"name":"","score":0},{"name":"","score":0},{"name":"","score":0}]`,
+			want: []HighScore{
+				{Name: "AAA", Score: 300, Mode: sim.ModeClassic, Difficulty: "normal"},
+				{Name: "BBB", Score: 120, Mode: sim.ModeClassic, Difficulty: "normal"},
+			},
+			migrated: true,
+		},
+		{
+			name: "v2",
+			data: `{"version":2,"scores":[{"name":"BOB","score":900,"mode":"endless","difficulty":"hard","bombs_used":0,` +
+				`"time":"2025-01-02T03:04:05Z","distance":4500,"coins":12,"seed":42,"version":"1.0.2"}]}`,
+			want: []HighScore{{
+				Name: "BOB", Score: 900, Mode: sim.ModeEndless, Difficulty: "hard", BombsUsed: &zero,
+				Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Distance: 4500, Coins: 12, Seed: 42, Version: "1.0.2",
+			}},
+			migrated: true,
+		},
+		{
+			name: "v3",
+			data: string(current),
+			want: []HighScore{{Name: "CAT", Score: 500, Mode: sim.ModeEndless, Difficulty: "easy", BombsUsed: &zero}},
+		},
+		{
+			name: "v3 bad checksum",
+			data: `{"version":3,"checksum":"00","scores":[{"name":"CAT","score":500}]}`,
+			err:  ErrHighScoreChecksum,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, migrated, err := unmarshalHighScores([]byte(tt.data))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scores = %+v, want %+v", got, tt.want)
+			}
+			if migrated != tt.migrated {
+				t.Errorf("migrated = %v, want %v", migrated, tt.migrated)
+			}
+		})
+	}
+}
+
+func TestUnmarshalHighScoresFutureVersion(t *testing.T) {
+	if _, _, err := unmarshalHighScores([]byte(`{"version":4,"scores":[]}`)); err == nil {
+		t.Error("expected an error for an unknown file version")
+	}
+}
@@ -2,10 +2,7 @@
 
 package rush
 
-import (
-	"encoding/json"
-	"syscall/js"
-)
+import "syscall/js"
 
 type wasmHighScoreStorage struct{}
 
@@ -13,28 +10,50 @@ func newHighScoreStorage() HighScoreStorage {
 	return &wasmHighScoreStorage{}
 }
 
-const wasmHighScoreKey = "rush_highscores"
+const (
+	wasmHighScoreKey       = "rush_highscores"
+	wasmHighScoreBackupKey = "rush_highscores_bak" // 上一次保存的排行榜
+)
 
+// Save 写入新的排行榜，原来的内容轮换为备份。localStorage 的单次写入本身是原子的，
+// 超出配额等异常作为错误返回。
 func (s *wasmHighScoreStorage) Save(highScores []HighScore) error {
-	data, err := json.Marshal(highScores)
+	data, err := marshalHighScores(highScores)
 	if err != nil {
 		return err
 	}
-	js.Global().Get("localStorage").Call("setItem", wasmHighScoreKey, string(data))
-	return nil
+	storage := js.Global().Get("localStorage")
+	if old := storage.Call("getItem", wasmHighScoreKey); !old.IsNull() && !old.IsUndefined() {
+		if err := localStorageSet(wasmHighScoreBackupKey, old.String()); err != nil {
+			return err
+		}
+	}
+	return localStorageSet(wasmHighScoreKey, string(data))
 }
 
+// Load 读取排行榜，损坏时从备份恢复并写回
 func (s *wasmHighScoreStorage) Load() ([]HighScore, error) {
-	item := js.Global().Get("localStorage").Call("getItem", wasmHighScoreKey)
+	storage := js.Global().Get("localStorage")
+	item := storage.Call("getItem", wasmHighScoreKey)
 	if item.IsNull() || item.IsUndefined() {
-		return make([]HighScore, 5), nil
+		return nil, nil
 	}
-	var loaded []HighScore
-	if err := json.Unmarshal([]byte(item.String()), &loaded); err != nil {
+	loaded, migrated, err := unmarshalHighScores([]byte(item.String()))
+	if err == nil {
+		if migrated {
+			_ = s.Save(loaded)
+		}
+		return loaded, nil
+	}
+
+	backup := storage.Call("getItem", wasmHighScoreBackupKey)
+	if backup.IsNull() || backup.IsUndefined() {
 		return nil, err
 	}
-	for len(loaded) < 5 {
-		loaded = append(loaded, HighScore{"", 0})
+	loaded, _, backupErr := unmarshalHighScores([]byte(backup.String()))
+	if backupErr != nil {
+		return nil, err
 	}
-	return loaded[:5], nil
+	_ = localStorageSet(wasmHighScoreKey, backup.String())
+	return loaded, ErrHighScoresRecovered
 }
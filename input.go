@@ -0,0 +1,119 @@
+package rush
+
+import (
+	"image"
+
+	"rush/sim"
+)
+
+// Action 逻辑输入动作，多个动作可按位组合
+type Action uint32
+
+const (
+	ActionUp      Action = 1 << iota // 上升 / 菜单上移
+	ActionDown                       // 菜单下移
+	ActionLeft                       // 菜单左移
+	ActionRight                      // 菜单右移
+	ActionBomb                       // 释放炸弹
+	ActionPause                      // 暂停 / 继续
+	ActionConfirm                    // 确认
+	ActionBack                       // 返回 / 退出
+	ActionErase                      // 删除字符
+	ActionEnd                        // 结束名字输入
+	ActionYes                        // 确认退出
+	ActionNo                         // 取消退出
+)
+
+// Pointer 指针（鼠标左键或触摸点）位置
+type Pointer struct {
+	image.Point
+	Touch bool // 是否来自触摸屏
+}
+
+// InputFrame 一帧的逻辑输入
+type InputFrame struct {
+	Held     Action    // 当前按住的动作
+	Pressed  Action    // 本帧刚按下的动作
+	Pointers []Pointer // 当前按住的指针
+	Taps     []Pointer // 本帧刚按下的指针
+}
+
+// IsHeld 检查动作是否处于按住状态
+func (f InputFrame) IsHeld(a Action) bool {
+	return f.Held&a != 0
+}
+
+// IsPressed 检查动作是否在本帧刚按下
+func (f InputFrame) IsPressed(a Action) bool {
+	return f.Pressed&a != 0
+}
+
+// Tapped 检查本帧是否有指针刚按下
+func (f InputFrame) Tapped() bool {
+	return len(f.Taps) > 0
+}
+
+// TapIn 检查本帧是否有指针在指定矩形内刚按下
+func (f InputFrame) TapIn(r image.Rectangle) bool {
+	for _, p := range f.Taps {
+		if p.In(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// PointerIn 检查是否有按住的指针位于指定矩形内
+func (f InputFrame) PointerIn(r image.Rectangle) bool {
+	for _, p := range f.Pointers {
+		if p.In(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// simInput 把一帧逻辑输入换算为模拟输入，upButton、bombButton 为屏幕上的上升和炸弹按钮
+func simInput(f InputFrame, upButton, bombButton image.Rectangle) sim.Input {
+	return sim.Input{
+		Up:   f.IsHeld(ActionUp) || f.PointerIn(upButton),
+		Bomb: f.IsPressed(ActionBomb) || f.TapIn(bombButton),
+	}
+}
+
+// InputSource 逐帧提供逻辑输入
+type InputSource interface {
+	// Poll 读取下一帧的输入，每帧调用一次
+	Poll() InputFrame
+}
+
+// ScriptedInput 按预先编写的帧序列提供输入，可用于测试、机器人和远程控制
+type ScriptedInput struct {
+	frames []InputFrame
+	pos    int
+}
+
+// NewScriptedInput 创建脚本输入源
+func NewScriptedInput(frames ...InputFrame) *ScriptedInput {
+	return &ScriptedInput{frames: frames}
+}
+
+// Push 在脚本末尾追加输入帧
+func (s *ScriptedInput) Push(frames ...InputFrame) {
+	s.frames = append(s.frames, frames...)
+}
+
+// Poll 返回下一帧输入，脚本用完后返回空输入
+func (s *ScriptedInput) Poll() InputFrame {
+	if s.pos >= len(s.frames) {
+		return InputFrame{}
+	}
+	f := s.frames[s.pos]
+	s.pos++
+	return f
+}
+
+// Done 检查脚本是否已经播放完毕
+func (s *ScriptedInput) Done() bool {
+	return s.pos >= len(s.frames)
+}
@@ -0,0 +1,72 @@
+package rush
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// keyActions 键盘按键与逻辑动作的对应关系
+var keyActions = []struct {
+	key    ebiten.Key
+	action Action
+}{
+	{ebiten.KeyUp, ActionUp},
+	{ebiten.KeyDown, ActionDown},
+	{ebiten.KeyLeft, ActionLeft},
+	{ebiten.KeyRight, ActionRight},
+	{ebiten.KeyX, ActionBomb},
+	{ebiten.KeyZ, ActionPause},
+	{ebiten.KeyEnter, ActionConfirm},
+	{ebiten.KeyEscape, ActionBack},
+	{ebiten.KeyBackspace, ActionErase},
+	{ebiten.KeySpace, ActionEnd},
+	{ebiten.KeyY, ActionYes},
+	{ebiten.KeyN, ActionNo},
+}
+
+// ebitenInput 基于键盘、鼠标和触摸屏的输入源
+type ebitenInput struct {
+	touchIDs []ebiten.TouchID
+}
+
+// NewEbitenInput 创建读取键盘、鼠标和触摸屏的输入源
+func NewEbitenInput() InputSource {
+	return &ebitenInput{}
+}
+
+func (in *ebitenInput) Poll() InputFrame {
+	var f InputFrame
+	for _, ka := range keyActions {
+		if ebiten.IsKeyPressed(ka.key) {
+			f.Held |= ka.action
+		}
+		if inpututil.IsKeyJustPressed(ka.key) {
+			f.Pressed |= ka.action
+		}
+	}
+
+	// 鼠标左键
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		f.Pointers = append(f.Pointers, Pointer{Point: image.Pt(x, y)})
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		f.Taps = append(f.Taps, Pointer{Point: image.Pt(x, y)})
+	}
+
+	// 触摸屏
+	for _, id := range ebiten.TouchIDs() {
+		x, y := ebiten.TouchPosition(id)
+		f.Pointers = append(f.Pointers, Pointer{Point: image.Pt(x, y), Touch: true})
+	}
+	in.touchIDs = inpututil.AppendJustPressedTouchIDs(in.touchIDs[:0])
+	for _, id := range in.touchIDs {
+		x, y := ebiten.TouchPosition(id)
+		f.Taps = append(f.Taps, Pointer{Point: image.Pt(x, y), Touch: true})
+	}
+
+	return f
+}
@@ -0,0 +1,53 @@
+package rush
+
+import (
+	"image"
+	"testing"
+
+	"rush/sim"
+)
+
+// press 返回刚按下动作 a 的一帧输入
+func press(a Action) InputFrame {
+	return InputFrame{Held: a, Pressed: a}
+}
+
+// touch 返回触摸点 (x, y)
+func touch(x, y int) []Pointer {
+	return []Pointer{{Point: image.Pt(x, y), Touch: true}}
+}
+
+func TestScriptedInputMapping(t *testing.T) {
+	upButton := image.Rect(115, 35, 155, 75)
+	bombButton := image.Rect(5, 35, 45, 75)
+	in := NewScriptedInput()
+
+	steps := []struct {
+		name   string
+		frames []InputFrame
+		want   sim.Input // 最后一帧换算出的模拟输入
+	}{
+		{"idle", []InputFrame{{}}, sim.Input{}},
+		{"hold up key", []InputFrame{press(ActionUp), {Held: ActionUp}}, sim.Input{Up: true}},
+		{"release up key", []InputFrame{{}}, sim.Input{}},
+		{"press bomb key", []InputFrame{press(ActionBomb)}, sim.Input{Bomb: true}},
+		{"hold bomb key", []InputFrame{{Held: ActionBomb}}, sim.Input{}},
+		{"hold up button", []InputFrame{{Pointers: touch(130, 50)}}, sim.Input{Up: true}},
+		{"tap bomb button", []InputFrame{{Pointers: touch(20, 50), Taps: touch(20, 50)}}, sim.Input{Bomb: true}},
+		{"tap outside buttons", []InputFrame{{Pointers: touch(80, 10), Taps: touch(80, 10)}}, sim.Input{}},
+		{"climb and bomb", []InputFrame{{Held: ActionUp | ActionBomb, Pressed: ActionBomb}}, sim.Input{Up: true, Bomb: true}},
+	}
+	for _, s := range steps {
+		in.Push(s.frames...)
+		var got sim.Input
+		for !in.Done() {
+			got = simInput(in.Poll(), upButton, bombButton)
+		}
+		if got != s.want {
+			t.Errorf("%s: input = %+v, want %+v", s.name, got, s.want)
+		}
+	}
+	if f := in.Poll(); f.Held != 0 || f.Pressed != 0 || f.Tapped() {
+		t.Errorf("finished script returned %+v, want an empty frame", f)
+	}
+}
@@ -0,0 +1,99 @@
+package rush
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	DefaultLeaderboardSize = 100 // 每种模式排行榜默认保留的记录数
+	leaderboardPageRows    = 5   // 排行榜每页显示的行数
+)
+
+// leaderboardPageRect 排行榜右下角的页码，点击翻到下一页
+var leaderboardPageRect = image.Rect(112, 70, screenWidth, screenHeight)
+
+// SetLeaderboardSize 设置每种模式排行榜显示的记录数，存储中的记录不受影响
+func (g *Game) SetLeaderboardSize(n int) error {
+	if n < 1 {
+		return fmt.Errorf("leaderboard size must be positive: %d", n)
+	}
+	g.leaderboardSize = n
+	return nil
+}
+
+// boardScores 返回当前模式、当前规则排行榜的全部记录，按分数从高到低排列
+func (g *Game) boardScores() []HighScore {
+	key := g.ruleset()
+	var board []HighScore
+	for _, hs := range highScores[g.mode] {
+		if hs.Rules == key {
+			board = append(board, hs)
+		}
+	}
+	return board
+}
+
+// visibleHighScores 返回排行榜显示的记录，最多 leaderboardSize 条
+func (g *Game) visibleHighScores() []HighScore {
+	board := g.boardScores()
+	return board[:min(len(board), g.leaderboardSize)]
+}
+
+// leaderboardPages 返回当前模式排行榜的页数，空榜也有一页
+func (g *Game) leaderboardPages() int {
+	return max(1, (len(g.visibleHighScores())+leaderboardPageRows-1)/leaderboardPageRows)
+}
+
+// ownRank 返回玩家自己的记录在当前规则排行榜中的名次（从 0 开始），没有时返回 -1
+func (g *Game) ownRank() int {
+	return slices.IndexFunc(g.boardScores(), func(hs HighScore) bool { return hs.Own })
+}
+
+// openHighScores 进入排行榜界面，翻到自己的名次所在的页
+func (g *Game) openHighScores(state GameState) {
+	g.openHighScoresAt(state, g.ownRank())
+}
+
+// openHighScoresAt 进入排行榜界面，翻到名次 rank 所在的页，名次不在显示范围内时显示第一页
+func (g *Game) openHighScoresAt(state GameState, rank int) {
+	g.highScorePage = 0
+	if rank >= 0 && rank < len(g.visibleHighScores()) {
+		g.highScorePage = rank / leaderboardPageRows
+	}
+	g.state = state
+}
+
+// updateHighScorePage 处理排行榜翻页，返回是否翻了页
+func (g *Game) updateHighScorePage() bool {
+	pages := g.leaderboardPages()
+	switch {
+	case g.in.IsPressed(ActionDown | ActionRight), g.in.TapIn(leaderboardPageRect):
+		g.highScorePage = (g.highScorePage + 1) % pages
+	case g.in.IsPressed(ActionUp | ActionLeft):
+		g.highScorePage = (g.highScorePage + pages - 1) % pages
+	default:
+		return false
+	}
+	return true
+}
+
+// drawOwnRank 自己的名次不在当前页时显示在左下角
+func (g *Game) drawOwnRank(screen *ebiten.Image) {
+	rank := g.ownRank()
+	if rank < 0 || rank/leaderboardPageRows == g.highScorePage {
+		return
+	}
+	text := fmt.Sprintf("YOU #%d %d", rank+1, g.boardScores()[rank].Score)
+	drawHandDrawnText(screen, text, 2, 71, color.RGBA{255, 0, 0, 255})
+}
+
+// drawLeaderboardPage 在右下角绘制页码
+func (g *Game) drawLeaderboardPage(screen *ebiten.Image) {
+	text := fmt.Sprintf("%d/%d", g.highScorePage+1, g.leaderboardPages())
+	drawHandDrawnText(screen, text, screenWidth-2-len(text)*8, 71, color.RGBA{128, 128, 128, 255})
+}
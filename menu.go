@@ -0,0 +1,128 @@
+package rush
+
+import (
+	"image"
+	"image/color"
+
+	"rush/sim"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	menuListTop     = 14 // 第一行菜单的Y坐标
+	menuListRowH    = 10 // 每行高度
+	menuListVisible = 6  // 一屏可显示的行数
+)
+
+// moreButtonRect 标题界面上"MORE"按钮的位置（原图第5个菜单位置）
+var moreButtonRect = image.Rect(122, 68, 122+34, 68+9)
+
+// replaysButtonRect 标题界面上"REPLAY"按钮的位置，在"MORE"按钮左侧
+var replaysButtonRect = image.Rect(68, 68, 68+50, 68+9)
+
+// menuItem 文字菜单中的一项
+type menuItem struct {
+	label  string
+	action func()
+}
+
+// moreMenuItems 返回"MORE"菜单的选项
+func (g *Game) moreMenuItems() []menuItem {
+	items := []menuItem{
+		{"Endless mode", func() { g.startNewGame(sim.ModeEndless) }},
+		{"Courses", func() { g.courseChoice = 0; g.state = StateCourses }},
+		{"Course editor", g.openEditor},
+	}
+	items = append(items, g.ruleMenuItems()...)
+	return append(items, []menuItem{
+		{"Achievements", func() { g.achievementChoice = 0; g.state = StateAchievements }},
+		{"Statistics", func() { g.statsChoice = 0; g.state = StateStats }},
+		{"Back", func() { g.state = StateTitle }},
+	}...)
+}
+
+// menuListFirstRow 返回当前可见的第一行，保证选中项可见
+func menuListFirstRow(choice int) int {
+	if choice >= menuListVisible {
+		return choice - menuListVisible + 1
+	}
+	return 0
+}
+
+// menuListRowRect 返回第 row 个可见行的区域
+func menuListRowRect(row int) image.Rectangle {
+	y := menuListTop + row*menuListRowH
+	return image.Rect(0, y, screenWidth, y+menuListRowH)
+}
+
+// updateMenuList 处理文字菜单的导航与选择，按下返回键时返回true
+func (g *Game) updateMenuList(items []menuItem, choice *int) bool {
+	if g.in.IsPressed(ActionDown) {
+		*choice = (*choice + 1) % len(items)
+	}
+	if g.in.IsPressed(ActionUp) {
+		*choice--
+		if *choice < 0 {
+			*choice = len(items) - 1
+		}
+	}
+	if g.in.IsPressed(ActionBack) {
+		return true
+	}
+
+	first := menuListFirstRow(*choice)
+	for row := 0; row < menuListVisible && first+row < len(items); row++ {
+		if g.in.TapIn(menuListRowRect(row)) {
+			*choice = first + row
+			items[*choice].action()
+			return false
+		}
+	}
+	if g.in.IsPressed(ActionConfirm) {
+		items[*choice].action()
+	}
+	return false
+}
+
+// drawMenuList 绘制带标题的文字菜单
+func drawMenuList(screen *ebiten.Image, title string, items []menuItem, choice int) {
+	screen.Fill(color.White)
+	drawHandDrawnText(screen, title, (screenWidth-len(title)*8)/2, 2, color.Black)
+
+	first := menuListFirstRow(choice)
+	for row := 0; row < menuListVisible && first+row < len(items); row++ {
+		r := menuListRowRect(row)
+		if first+row == choice {
+			drawSelector(screen, r, color.RGBA{R: 70, G: 130, B: 180, A: 128})
+		}
+		drawHandDrawnText(screen, items[first+row].label, 4, r.Min.Y+1, color.RGBA{0, 0, 128, 255})
+	}
+}
+
+// updateMore 处理"MORE"菜单输入
+func (g *Game) updateMore() error {
+	if g.updateMenuList(g.moreMenuItems(), &g.moreChoice) {
+		g.state = StateTitle
+	}
+	return nil
+}
+
+// drawMore 绘制"MORE"菜单
+func (g *Game) drawMore(screen *ebiten.Image) {
+	drawMenuList(screen, "MORE", g.moreMenuItems(), g.moreChoice)
+}
+
+// drawMoreButton 在标题界面绘制"MORE"和"REPLAY"按钮
+func drawMoreButton(screen *ebiten.Image) {
+	drawTextButton(screen, moreButtonRect, "MORE")
+	drawTextButton(screen, replaysButtonRect, "REPLAY")
+}
+
+// drawTextButton 绘制白底黑框的文字按钮
+func drawTextButton(screen *ebiten.Image, r image.Rectangle, label string) {
+	vector.DrawFilledRect(screen, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), color.White, false)
+	vector.StrokeRect(screen, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), 1, color.Black, false)
+	drawHandDrawnText(screen, label, r.Min.X+1, r.Min.Y+1, color.Black)
+}
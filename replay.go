@@ -0,0 +1,152 @@
+package rush
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"rush/sim"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// storedReplay 回放列表中的一项
+type storedReplay struct {
+	name   string
+	replay *sim.Replay
+}
+
+// PlayReplay 从倒计时开始播放一段回放，播放结束后恢复玩家原来的规则设置
+func (g *Game) PlayReplay(r *sim.Replay) error {
+	if err := r.Check(); err != nil {
+		return err
+	}
+	if g.savedRules == nil {
+		saved := g.rules()
+		g.savedRules = &saved
+	}
+	// 先换成回放的规则再重置，重置后的界面和按钮按回放的规则准备
+	g.applyRules(r.Rules)
+	g.reset()
+	g.useSeed(r.Seed)
+	g.sim = r.NewSimulation()
+	g.recording = nil
+	g.ghost = nil
+	g.playback = r
+	g.playbackFrame = 0
+	// 回放不参与成就判定
+	g.run = achievementRun{}
+	g.state = StateCountdown
+	return nil
+}
+
+// endPlayback 结束回放播放，恢复开始播放前玩家的规则设置
+func (g *Game) endPlayback() {
+	if g.playback == nil {
+		return
+	}
+	g.playback = nil
+	if g.savedRules != nil {
+		g.applyRules(*g.savedRules)
+		g.savedRules = nil
+	}
+}
+
+// openReplays 读取已保存的回放并进入回放列表，最近一局在最前，其余按分数从高到低
+func (g *Game) openReplays() {
+	names, err := g.replayStorage.ListReplays()
+	if err != nil {
+		log.Printf("Failed to list replays: %v", err)
+	}
+	g.replays = g.replays[:0]
+	for _, name := range names {
+		r, err := g.replayStorage.LoadReplay(name)
+		if err != nil {
+			log.Printf("Failed to load replay %s: %v", name, err)
+			continue
+		}
+		g.replays = append(g.replays, storedReplay{name, r})
+	}
+	sort.SliceStable(g.replays, func(i, j int) bool {
+		a, b := g.replays[i], g.replays[j]
+		if (a.name == lastReplayName) != (b.name == lastReplayName) {
+			return a.name == lastReplayName
+		}
+		return a.replay.Score > b.replay.Score
+	})
+	g.replayChoice = 0
+	g.state = StateReplays
+}
+
+// replayMenuItems 返回回放列表，每行显示种子和分数
+func (g *Game) replayMenuItems() []menuItem {
+	items := make([]menuItem, 0, len(g.replays)+1)
+	for _, e := range g.replays {
+		kind := "BEST"
+		if e.name == lastReplayName {
+			kind = "LAST"
+		}
+		label := fmt.Sprintf("%-4s %8d %6d", kind, e.replay.Seed, e.replay.Score)
+		items = append(items, menuItem{label, func() {
+			if err := g.PlayReplay(e.replay); err != nil {
+				log.Printf("Failed to play replay %s: %v", e.name, err)
+				g.showMessage("Bad replay", 60)
+			}
+		}})
+	}
+	return append(items, menuItem{"Back", func() { g.state = StateTitle }})
+}
+
+// updateReplays 处理回放列表界面输入
+func (g *Game) updateReplays() error {
+	if g.updateMenuList(g.replayMenuItems(), &g.replayChoice) {
+		g.state = StateTitle
+	}
+	return nil
+}
+
+// drawReplays 绘制回放列表，没有回放时只有返回项
+func (g *Game) drawReplays(screen *ebiten.Image) {
+	drawMenuList(screen, "REPLAYS", g.replayMenuItems(), g.replayChoice)
+}
+
+// isPlayingBack 检查当前是否在播放回放
+func (g *Game) isPlayingBack() bool {
+	return g.playback != nil
+}
+
+// nextSimInput 返回本帧的模拟输入：播放回放时取回放中的记录，否则读取玩家输入并录制
+func (g *Game) nextSimInput() sim.Input {
+	if g.playback != nil {
+		if g.playbackFrame >= len(g.playback.Frames) {
+			return sim.Input{}
+		}
+		in := g.playback.Frames[g.playbackFrame]
+		g.playbackFrame++
+		return in
+	}
+
+	in := simInput(g.in, g.upButtonRect, g.bombButtonRect)
+	if g.recording != nil {
+		g.recording.Record(in)
+	}
+	return in
+}
+
+// playbackExhausted 检查回放的输入是否已经用完
+func (g *Game) playbackExhausted() bool {
+	return g.playback != nil && g.playbackFrame >= len(g.playback.Frames)
+}
+
+// finishRecording 对局结束时保存回放
+func (g *Game) finishRecording() {
+	if g.recording == nil {
+		return
+	}
+	g.recording.Finish(g.sim)
+	if err := g.replayStorage.SaveReplay(lastReplayName, g.recording); err != nil {
+		log.Printf("Failed to save replay: %v", err)
+	}
+	g.saveBestReplay(g.recording)
+	g.recording = nil
+}
@@ -0,0 +1,27 @@
+package rush
+
+import (
+	"errors"
+
+	"rush/sim"
+)
+
+// ErrReplayNotFound 指定名字的回放不存在
+var ErrReplayNotFound = errors.New("replay not found")
+
+// lastReplayName 最近一局回放的名字
+const lastReplayName = "last"
+
+// ReplayStorage 回放存储接口，回放与排行榜保存在同一位置
+type ReplayStorage interface {
+	SaveReplay(name string, r *sim.Replay) error
+	LoadReplay(name string) (*sim.Replay, error)
+	ListReplays() ([]string, error) // 返回已保存回放的名字
+	DeleteReplay(name string) error
+}
+
+// NewReplayStorage 返回当前平台的回放存储实现
+func NewReplayStorage() ReplayStorage {
+	// 具体实现由各平台的 build tag 文件提供
+	return newReplayStorage()
+}
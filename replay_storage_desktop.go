@@ -0,0 +1,65 @@
+//go:build !android && !js
+
+package rush
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"rush/sim"
+)
+
+type desktopReplayStorage struct{}
+
+func newReplayStorage() ReplayStorage {
+	return &desktopReplayStorage{}
+}
+
+// replayFile 返回回放文件路径，位于存档目录中
+func (s *desktopReplayStorage) replayFile(name string) string {
+	return filepath.Join(desktopDataDir(), "replay_"+name+".json")
+}
+
+func (s *desktopReplayStorage) SaveReplay(name string, r *sim.Replay) error {
+	var buf bytes.Buffer
+	if err := sim.WriteReplay(&buf, r); err != nil {
+		return err
+	}
+	return writeFileAtomic(s.replayFile(name), buf.Bytes())
+}
+
+func (s *desktopReplayStorage) LoadReplay(name string) (*sim.Replay, error) {
+	file, err := os.Open(s.replayFile(name))
+	if os.IsNotExist(err) {
+		return nil, ErrReplayNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return sim.ReadReplay(file)
+}
+
+func (s *desktopReplayStorage) ListReplays() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(desktopDataDir(), "replay_*.json"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "replay_"), ".json"))
+	}
+	return names, nil
+}
+
+func (s *desktopReplayStorage) DeleteReplay(name string) error {
+	// 保存时轮换出的备份一并删除
+	os.Remove(backupPath(s.replayFile(name)))
+	err := os.Remove(s.replayFile(name))
+	if os.IsNotExist(err) {
+		return ErrReplayNotFound
+	}
+	return err
+}
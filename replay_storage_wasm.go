@@ -0,0 +1,67 @@
+//go:build js && wasm
+
+package rush
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"syscall/js"
+
+	"rush/sim"
+)
+
+type wasmReplayStorage struct{}
+
+func newReplayStorage() ReplayStorage {
+	return &wasmReplayStorage{}
+}
+
+const wasmReplayKeyPrefix = "rush_replay_"
+
+func (s *wasmReplayStorage) SaveReplay(name string, r *sim.Replay) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return localStorageSet(wasmReplayKeyPrefix+name, string(data))
+}
+
+func (s *wasmReplayStorage) LoadReplay(name string) (*sim.Replay, error) {
+	item := js.Global().Get("localStorage").Call("getItem", wasmReplayKeyPrefix+name)
+	if item.IsNull() || item.IsUndefined() {
+		return nil, ErrReplayNotFound
+	}
+	r := &sim.Replay{}
+	if err := json.Unmarshal([]byte(item.String()), r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *wasmReplayStorage) ListReplays() ([]string, error) {
+	storage := js.Global().Get("localStorage")
+	var names []string
+	for i := 0; i < storage.Get("length").Int(); i++ {
+		if key := storage.Call("key", i).String(); strings.HasPrefix(key, wasmReplayKeyPrefix) {
+			names = append(names, strings.TrimPrefix(key, wasmReplayKeyPrefix))
+		}
+	}
+	return names, nil
+}
+
+func (s *wasmReplayStorage) DeleteReplay(name string) error {
+	js.Global().Get("localStorage").Call("removeItem", wasmReplayKeyPrefix+name)
+	return nil
+}
+
+// localStorageSet 写入 localStorage，超出配额时浏览器抛出的异常作为错误返回
+func localStorageSet(key, value string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("localStorage: %v", r)
+		}
+	}()
+	js.Global().Get("localStorage").Call("setItem", key, value)
+	return nil
+}
@@ -6,13 +6,19 @@ import (
 	"fmt"
 	"image/color"
 	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
 	"sync"
 
+	"rush/sim"
+
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 )
 
-//go:embed assets/images
+//go:embed assets/images assets/courses
 var assetsFS embed.FS
 
 // ResourceType 定义资源类型
@@ -26,19 +32,60 @@ const (
 	ResourceCoin          ResourceType = "coin"
 	ResourceBomb          ResourceType = "bomb"
 	ResourceHandDrawnFont ResourceType = "handdrawn_font"
+	ResourceDiamond       ResourceType = "diamond"
+	ResourceRedFish       ResourceType = "redfish"
+	ResourceShield        ResourceType = "shield"
+	ResourceTimeSlow      ResourceType = "timeslow"
+	ResourceMagnet        ResourceType = "magnet"
+	ResourceRock          ResourceType = "rock"
+	ResourceStalactite    ResourceType = "stalactite"
+	ResourceMine          ResourceType = "mine"
+	ResourceFish          ResourceType = "fish"
+	ResourceJellyfish     ResourceType = "jellyfish"
 )
 
+// collectibleResources 各类道具使用的图像，炸弹补给沿用炸弹图标
+var collectibleResources = [sim.NumCollectibleKinds]ResourceType{
+	sim.KindCoin:       ResourceCoin,
+	sim.KindDiamond:    ResourceDiamond,
+	sim.KindRedFish:    ResourceRedFish,
+	sim.KindBombRefill: ResourceBomb,
+	sim.KindShield:     ResourceShield,
+	sim.KindTimeSlow:   ResourceTimeSlow,
+	sim.KindMagnet:     ResourceMagnet,
+}
+
+// obstacleResources 各类障碍物使用的图像
+var obstacleResources = [sim.NumObstacleKinds]ResourceType{
+	sim.ObstacleRock:       ResourceRock,
+	sim.ObstacleStalactite: ResourceStalactite,
+	sim.ObstacleMine:       ResourceMine,
+}
+
+// enemyResources 各类敌人使用的图像
+var enemyResources = [sim.NumEnemyKinds]ResourceType{
+	sim.EnemyFish:      ResourceFish,
+	sim.EnemyJellyfish: ResourceJellyfish,
+}
+
 // ResourceManager 资源管理器
 type ResourceManager struct {
-	cache  map[ResourceType]*ebiten.Image
-	mutex  sync.RWMutex
-	loaded bool
+	cache   map[ResourceType]*ebiten.Image
+	masks   map[ResourceType]*sim.Mask // 由图像 alpha 生成的碰撞掩码
+	courses map[string]*sim.Course
+	mutex   sync.RWMutex
+	loaded  bool
 }
 
+// 内置关卡所在目录
+const coursesDir = "assets/courses"
+
 // NewResourceManager 创建新的资源管理器
 func NewResourceManager() *ResourceManager {
 	return &ResourceManager{
-		cache: make(map[ResourceType]*ebiten.Image),
+		cache:   make(map[ResourceType]*ebiten.Image),
+		masks:   make(map[ResourceType]*sim.Mask),
+		courses: make(map[string]*sim.Course),
 	}
 }
 
@@ -72,13 +119,14 @@ func (rm *ResourceManager) LoadResource(resourceType ResourceType) (*ebiten.Imag
 	}
 
 	// 创建图像
-	img, _, err := ebitenutil.NewImageFromReader(bytes.NewReader(b))
+	img, src, err := ebitenutil.NewImageFromReader(bytes.NewReader(b))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create image for %s: %w", resourceType, err)
 	}
 
 	// 缓存资源
 	rm.cache[resourceType] = img
+	rm.masks[resourceType] = sim.MaskFromImage(src)
 	log.Printf("Loaded resource: %s", resourceType)
 
 	return img, nil
@@ -111,6 +159,16 @@ func (rm *ResourceManager) PreloadResources() error {
 		ResourceCoin,
 		ResourceBomb,
 		ResourceHandDrawnFont,
+		ResourceDiamond,
+		ResourceRedFish,
+		ResourceShield,
+		ResourceTimeSlow,
+		ResourceMagnet,
+		ResourceRock,
+		ResourceStalactite,
+		ResourceMine,
+		ResourceFish,
+		ResourceJellyfish,
 	}
 
 	for _, resourceType := range resourceTypes {
@@ -124,12 +182,13 @@ func (rm *ResourceManager) PreloadResources() error {
 			return fmt.Errorf("failed to read resource %s: %w", resourceType, err)
 		}
 
-		img, _, err := ebitenutil.NewImageFromReader(bytes.NewReader(b))
+		img, src, err := ebitenutil.NewImageFromReader(bytes.NewReader(b))
 		if err != nil {
 			return fmt.Errorf("failed to create image for %s: %w", resourceType, err)
 		}
 
 		rm.cache[resourceType] = img
+		rm.masks[resourceType] = sim.MaskFromImage(src)
 		log.Printf("Preloaded resource: %s", resourceType)
 	}
 
@@ -143,6 +202,39 @@ func (rm *ResourceManager) GetResource(resourceType ResourceType) *ebiten.Image
 	return rm.LoadResourceSafe(resourceType)
 }
 
+// GetMask 获取资源的碰撞掩码，如果未加载则自动加载
+func (rm *ResourceManager) GetMask(resourceType ResourceType) *sim.Mask {
+	if rm.LoadResourceSafe(resourceType) == nil {
+		return nil
+	}
+	rm.mutex.RLock()
+	defer rm.mutex.RUnlock()
+	return rm.masks[resourceType]
+}
+
+// CollisionMasks 返回逐像素碰撞使用的精灵掩码
+func (rm *ResourceManager) CollisionMasks() *sim.Masks {
+	masks := &sim.Masks{
+		Player:    rm.GetMask(ResourceSubmarine),
+		Coin:      rm.GetMask(ResourceCoin),
+		Items:     make(map[sim.CollectibleKind]*sim.Mask),
+		Obstacles: make(map[sim.ObstacleKind]*sim.Mask),
+		Enemies:   make(map[sim.EnemyKind]*sim.Mask),
+	}
+	for kind, rt := range collectibleResources {
+		if kind := sim.CollectibleKind(kind); kind != sim.KindCoin {
+			masks.Items[kind] = rm.GetMask(rt)
+		}
+	}
+	for kind, rt := range obstacleResources {
+		masks.Obstacles[sim.ObstacleKind(kind)] = rm.GetMask(rt)
+	}
+	for kind, rt := range enemyResources {
+		masks.Enemies[sim.EnemyKind(kind)] = rm.GetMask(rt)
+	}
+	return masks
+}
+
 // IsResourceLoaded 检查资源是否已加载
 func (rm *ResourceManager) IsResourceLoaded(resourceType ResourceType) bool {
 	rm.mutex.RLock()
@@ -156,6 +248,8 @@ func (rm *ResourceManager) ClearCache() {
 	rm.mutex.Lock()
 	defer rm.mutex.Unlock()
 	rm.cache = make(map[ResourceType]*ebiten.Image)
+	rm.masks = make(map[ResourceType]*sim.Mask)
+	rm.courses = make(map[string]*sim.Course)
 	rm.loaded = false
 	log.Println("Resource cache cleared")
 }
@@ -184,11 +278,86 @@ func (rm *ResourceManager) getResourcePath(resourceType ResourceType) (string, e
 		return "assets/images/bomb.png", nil
 	case ResourceHandDrawnFont:
 		return "assets/images/handdrawn_font.png", nil
+	case ResourceDiamond:
+		return "assets/images/diamond.png", nil
+	case ResourceRedFish:
+		return "assets/images/redfish.png", nil
+	case ResourceShield:
+		return "assets/images/shield.png", nil
+	case ResourceTimeSlow:
+		return "assets/images/timeslow.png", nil
+	case ResourceMagnet:
+		return "assets/images/magnet.png", nil
+	case ResourceRock:
+		return "assets/images/rock.png", nil
+	case ResourceStalactite:
+		return "assets/images/stalactite.png", nil
+	case ResourceMine:
+		return "assets/images/mine.png", nil
+	case ResourceFish:
+		return "assets/images/fish.png", nil
+	case ResourceJellyfish:
+		return "assets/images/jellyfish.png", nil
 	default:
 		return "", fmt.Errorf("unknown resource type: %s", resourceType)
 	}
 }
 
+// CourseNames 返回所有内置关卡的名字
+func (rm *ResourceManager) CourseNames() []string {
+	entries, err := assetsFS.ReadDir(coursesDir)
+	if err != nil {
+		log.Printf("Failed to list courses: %v", err)
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".json"); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadCourse 加载内置关卡
+func (rm *ResourceManager) LoadCourse(name string) (*sim.Course, error) {
+	rm.mutex.RLock()
+	c, exists := rm.courses[name]
+	rm.mutex.RUnlock()
+	if exists {
+		return c, nil
+	}
+
+	b, err := assetsFS.ReadFile(path.Join(coursesDir, name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read course %s: %w", name, err)
+	}
+	c, err = sim.ParseCourse(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse course %s: %w", name, err)
+	}
+
+	rm.mutex.Lock()
+	rm.courses[name] = c
+	rm.mutex.Unlock()
+	log.Printf("Loaded course: %s", name)
+	return c, nil
+}
+
+// LoadCourseFile 从磁盘加载关卡文件，不缓存
+func (rm *ResourceManager) LoadCourseFile(path string) (*sim.Course, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := sim.ParseCourse(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse course %s: %w", path, err)
+	}
+	return c, nil
+}
+
 // CreateFallbackImage 创建降级图像
 func (rm *ResourceManager) CreateFallbackImage(width, height int, clr color.Color) *ebiten.Image {
 	img := ebiten.NewImage(width, height)
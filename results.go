@@ -0,0 +1,57 @@
+package rush
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// updateResults 处理分数明细界面输入，之后进入名字输入或结束本局，
+// 胜利的对局同样可以上榜，剩余炸弹的奖励和未使用炸弹的标记由此进入排行榜
+func (g *Game) updateResults() error {
+	if !g.in.IsPressed(ActionConfirm) && !g.in.Tapped() {
+		return nil
+	}
+	if g.isPlayingBack() {
+		g.endPlayback()
+	} else if g.isRankedRun() && g.isHighScore(g.sim.Score()) {
+		g.startNameInput()
+		return nil
+	}
+	g.state = g.afterRunState()
+	return nil
+}
+
+// drawResults 绘制本局的分数明细
+func (g *Game) drawResults(screen *ebiten.Image) {
+	screen.Fill(color.White)
+	drawHandDrawnText(screen, "RESULTS", (screenWidth-7*8)/2, 2, color.Black)
+
+	b := g.sim.Breakdown()
+	rows := []struct {
+		label  string
+		points int
+	}{
+		{"DISTANCE", b.Distance},
+		{"ITEMS", b.Items},
+		{"COMBO", b.Combo},
+		{"NEAR MISS", b.NearMiss},
+		{"BOMB BONUS", b.BombBonus},
+	}
+	y := 12
+	for _, row := range rows {
+		drawResultRow(screen, row.label, row.points, y, color.RGBA{0, 0, 128, 255})
+		y += 10
+	}
+	vector.StrokeLine(screen, 4, float32(y-1), screenWidth-4, float32(y-1), 1, color.Black, false)
+	drawResultRow(screen, "TOTAL", g.sim.Score(), y, color.RGBA{128, 0, 0, 255})
+}
+
+// drawResultRow 绘制一行分数明细，分数右对齐
+func drawResultRow(screen *ebiten.Image, label string, points, y int, clr color.Color) {
+	text := fmt.Sprintf("%d", points)
+	drawHandDrawnText(screen, label, 4, y, clr)
+	drawHandDrawnText(screen, text, screenWidth-4-len(text)*8, y, clr)
+}
@@ -0,0 +1,172 @@
+package rush
+
+import (
+	"fmt"
+
+	"rush/sim"
+)
+
+// ruleOption "MORE"菜单中的一项规则选项
+type ruleOption struct {
+	name   string             // 菜单中显示的名字
+	value  func(*Game) string // 当前取值的显示文字
+	toggle func(*Game)        // 切换到下一个取值
+}
+
+// boolOption 返回开关类规则选项，field 返回 Game 中对应的字段
+func boolOption(name, on, off string, field func(*Game) *bool) ruleOption {
+	return ruleOption{
+		name: name,
+		value: func(g *Game) string {
+			if *field(g) {
+				return on
+			}
+			return off
+		},
+		toggle: func(g *Game) { *field(g) = !*field(g) },
+	}
+}
+
+// ruleOptions 规则选项表，按"MORE"菜单中的显示顺序排列，新的规则开关在此加一项即可
+var ruleOptions = []ruleOption{
+	{"Tunnel", (*Game).generatorName, (*Game).cycleGenerator},
+	{"Hit", func(g *Game) string { return g.collision.String() }, func(g *Game) {
+		if g.collision == sim.CollisionMask {
+			g.collision = sim.CollisionRect
+		} else {
+			g.collision = sim.CollisionMask
+		}
+	}},
+	boolOption("Items", "on", "off", func(g *Game) *bool { return &g.items }),
+	{"Refill", func(g *Game) string {
+		if g.bombRules != nil {
+			return "on"
+		}
+		return "off"
+	}, func(g *Game) {
+		if g.bombRules != nil {
+			g.bombRules = nil
+			return
+		}
+		bombs := sim.DefaultBombRules()
+		g.bombRules = &bombs
+	}},
+	boolOption("Rocks", "on", "off", func(g *Game) *bool { return &g.obstacles }),
+	boolOption("Enemies", "on", "off", func(g *Game) *bool { return &g.enemies }),
+	{"Bomb", func(g *Game) string {
+		if g.blastRadius > 0 {
+			return "blast"
+		}
+		return "classic"
+	}, func(g *Game) {
+		if g.blastRadius > 0 {
+			g.blastRadius = 0
+		} else {
+			g.blastRadius = sim.DefaultBlastRadius
+		}
+	}},
+	boolOption("Combo", "on", "off", func(g *Game) *bool { return &g.scoring }),
+	boolOption("Power-ups", "on", "off", func(g *Game) *bool { return &g.powerUps }),
+	{"Lives", func(g *Game) string {
+		if g.lives != nil {
+			return fmt.Sprint(g.lives.Count)
+		}
+		return "off"
+	}, func(g *Game) {
+		if g.lives != nil {
+			g.lives = nil
+			return
+		}
+		lives := sim.DefaultLivesRules()
+		g.lives = &lives
+	}},
+}
+
+// ruleMenuItems 返回规则选项表对应的菜单项
+func (g *Game) ruleMenuItems() []menuItem {
+	items := make([]menuItem, 0, len(ruleOptions))
+	for _, o := range ruleOptions {
+		items = append(items, menuItem{o.name + ":" + o.value(g), func() { o.toggle(g) }})
+	}
+	return items
+}
+
+// SetCollision 选择之后各局的碰撞判定方式
+func (g *Game) SetCollision(c sim.Collision) { g.collision = c }
+
+// SetItems 设置之后各局是否生成钻石、红鱼和炸弹补给
+func (g *Game) SetItems(enabled bool) { g.items = enabled }
+
+// SetObstacles 设置之后各局是否在随机隧道中生成障碍物
+func (g *Game) SetObstacles(enabled bool) { g.obstacles = enabled }
+
+// SetEnemies 设置之后各局是否在随机隧道中生成敌人
+func (g *Game) SetEnemies(enabled bool) { g.enemies = enabled }
+
+// SetScoring 设置之后各局是否启用连击倍率和擦墙奖励
+func (g *Game) SetScoring(enabled bool) { g.scoring = enabled }
+
+// SetPowerUps 设置之后各局是否生成护盾、时间减缓和磁铁，护盾抵挡一次撞击
+func (g *Game) SetPowerUps(enabled bool) { g.powerUps = enabled }
+
+// SetBombRules 设置之后各局的炸弹规则，nil 为原版（开局 3 个且无法补给）
+func (g *Game) SetBombRules(b *sim.BombRules) error {
+	if b != nil {
+		if err := b.Validate(); err != nil {
+			return err
+		}
+	}
+	g.bombRules = b
+	return nil
+}
+
+// SetBlastRadius 设置之后各局炸弹的爆炸半径，0 为原版的全屏清除
+func (g *Game) SetBlastRadius(radius int) error {
+	rules := sim.DefaultRules()
+	rules.Blast = radius
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+	g.blastRadius = radius
+	return nil
+}
+
+// SetLives 设置之后各局的多条命规则，nil 为原版的一条命
+func (g *Game) SetLives(l *sim.LivesRules) error {
+	if l != nil {
+		if err := l.Validate(); err != nil {
+			return err
+		}
+	}
+	g.lives = l
+	return nil
+}
+
+// rulesetKey 返回规则所属排行榜的标识，每套规则各有一个排行榜。
+// 模式分开保存、难度记录在每条记录中，两者不参与区分；与原版规则相同时为空，与旧版本的记录同榜
+func rulesetKey(r sim.Rules) string {
+	r.Mode = sim.ModeClassic
+	r.Difficulty = sim.Normal
+	r.Masks = nil
+	original := sim.DefaultRules()
+	original.Collision = sim.CollisionRect
+	if r.Equal(original) {
+		return ""
+	}
+	return r.Hash()
+}
+
+// ruleset 返回当前规则所属排行榜的标识
+func (g *Game) ruleset() string {
+	return rulesetKey(g.rules())
+}
+
+// isRankedRun 检查本局是否计入排行榜，开启新规则的对局进入各自规则的排行榜，只有编辑器试玩不计排名
+func (g *Game) isRankedRun() bool {
+	return !g.isTestPlaying()
+}
+
+// isStandardRun 检查本局能否解锁胜利和距离类成就，关卡、非原版生成器和多条命的对局不能
+func (g *Game) isStandardRun() bool {
+	return g.course == nil && g.generatorName() == sim.OriginalGenerator && g.lives == nil
+}
@@ -1,6 +1,7 @@
 package rush
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
@@ -10,19 +11,26 @@ import (
 	"math/rand"
 	"os"
 	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"time"
+
+	"rush/sim"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 const (
-	screenWidth  = 160
-	screenHeight = 80
+	screenWidth  = sim.ScreenWidth
+	screenHeight = sim.ScreenHeight
 
 	fontSize = 9
+
+	// maxSeed 随机种子的上限，保证种子位数较短，便于在屏幕上显示和分享
+	maxSeed = 100000000
 )
 
 // Game State
@@ -41,6 +49,14 @@ const (
 	StateExitConfirm
 	StateHighScores
 	StateHighScoresThenGame // 新增：排行榜后自动进入游戏
+	StateMore               // 更多功能菜单
+	StateDifficulty         // 难度选择
+	StateCourses            // 关卡选择
+	StateEditor             // 关卡编辑器
+	StateResults            // 分数明细
+	StateAchievements       // 成就列表
+	StateStats              // 累计统计
+	StateReplays            // 回放列表
 )
 
 var (
@@ -48,49 +64,65 @@ var (
 	backgroundColor = color.RGBA{70, 130, 180, 255} // SteelBlue
 )
 
+// itemMessages 吃到金币以外的道具时显示的消息
+var itemMessages = [sim.NumCollectibleKinds]string{
+	sim.KindDiamond:    "A diamond!!!",
+	sim.KindRedFish:    "A red fish!",
+	sim.KindBombRefill: "Bomb +1",
+	sim.KindShield:     "Shield!",
+	sim.KindTimeSlow:   "Slow down~",
+	sim.KindMagnet:     "Magnet!",
+}
+
 // 排行榜数据结构
 const highScoreFilePath = "highscores.json"
 
-type HighScore struct {
-	Name  string `json:"name"`
-	Score int    `json:"score"`
-}
+// GameVersion 游戏版本，记录在排行榜中，与 Android 的 versionName 保持一致
+const GameVersion = "1.0.3"
 
-var highScores [5]HighScore
+// HighScore 排行榜的一条记录，迁移来的旧记录没有时间、距离等信息
+type HighScore struct {
+	Name       string    `json:"name"`
+	Score      int       `json:"score"`
+	Mode       sim.Mode  `json:"mode,omitempty"`
+	Difficulty string    `json:"difficulty,omitempty"`
+	BombsUsed  *int      `json:"bombs_used,omitempty"` // 使用的炸弹数，旧记录没有该项
+	Time       time.Time `json:"time,omitzero"`        // 上榜时间
+	Distance   int       `json:"distance,omitempty"`   // 到达的距离
+	Coins      int       `json:"coins,omitempty"`      // 吃到的金币数
+	Seed       int64     `json:"seed,omitempty"`       // 本局的随机种子
+	Version    string    `json:"version,omitempty"`    // 上榜时的游戏版本
+	Rules      string    `json:"rules,omitempty"`      // 所属排行榜的规则标识，见 rulesetKey，空为原版规则
+	Own        bool      `json:"own,omitempty"`        // 是否为本机玩家在该排行榜上最近一次上榜的记录
+}
+
+// bombFree 检查该记录是否确定没有使用炸弹
+func (hs HighScore) bombFree() bool {
+	return hs.BombsUsed != nil && *hs.BombsUsed == 0
+}
+
+// 每种游戏模式单独排名，按分数从高到低排列
+var highScores [sim.NumModes][]HighScore
 var highScoreStorage HighScoreStorage
 
-type Player struct {
-	x, y float64
-	vy   float64 // Vertical velocity
-}
-
-type Tunnel struct {
-	x      float64
-	topY   float64
-	height float64
-	width  float64
-}
-
-type Collectible struct {
-	image *ebiten.Image
-	x, y  float64
-	w, h  int
-}
-
+// Game 是模拟核心的 ebiten 适配层，负责输入、界面状态和渲染
 type Game struct {
 	state           GameState
-	player          *Player
-	tunnels         []*Tunnel
-	collectibles    []*Collectible
-	distance        int
-	score           int
-	bombs           int
-	isBombing       bool
-	bombTimer       int
+	mode            sim.Mode        // 当前选择的游戏模式
+	difficulty      sim.Difficulty  // 当前选择的难度
+	course          *sim.Course     // 当前选择的关卡，nil 表示随机隧道
+	generator       string          // 当前选择的隧道生成器，空为原版
+	collision       sim.Collision   // 碰撞判定方式
+	items           bool            // 是否生成金币以外的道具
+	bombRules       *sim.BombRules  // 炸弹规则，nil 为原版
+	obstacles       bool            // 是否生成障碍物
+	enemies         bool            // 是否生成敌人
+	blastRadius     int             // 炸弹爆炸半径，0 为原版全屏清除
+	scoring         bool            // 是否启用连击倍率和擦墙奖励
+	lives           *sim.LivesRules // 多条命规则，nil 为原版的一条命
+	powerUps        bool            // 护盾抵挡一次撞击并出现磁铁
+	sim             *sim.Simulation // 当前局的模拟状态
 	countdownTimer  int
-	tunnelHeight    float64
-	tunnelTopY      float64
-	slope           int
 	upButtonRect    image.Rectangle
 	bombButtonRect  image.Rectangle
 	menuChoice      int
@@ -122,10 +154,56 @@ type Game struct {
 	eraseBoxHighlightTimer int
 	endBoxHighlightTimer   int
 
-	// 新增：原版道具生成相关变量
-	nextItem int // 下一个道具生成距离
-	thisItem int // 当前道具生成距离
-	itemPt   int // 道具指针
+	// 随机数相关：每局游戏使用独立的随机数发生器，保证可复现
+	rng          *rand.Rand // 界面表现（提示语）使用的随机数发生器
+	seed         int64      // 当前局使用的种子
+	fixedSeed    int64      // 外部指定的种子
+	hasFixedSeed bool       // 是否使用外部指定的种子
+
+	// 输入相关
+	input InputSource // 输入源
+	in    InputFrame  // 当前帧的输入
+
+	// "MORE"菜单当前选项
+	moreChoice int
+
+	// 关卡相关
+	courseChoice  int           // 关卡菜单当前选项
+	courseStorage CourseStorage // 自制关卡存储
+	editor        *courseEditor // 关卡编辑器，退出后保留以便继续编辑
+
+	// 难度选择相关
+	difficultyChoice int            // 难度菜单当前选项
+	customDifficulty sim.Difficulty // 自定义难度参数
+
+	// 回放相关
+	replayStorage ReplayStorage
+	recording     *sim.Replay    // 当前局的输入记录
+	playback      *sim.Replay    // 正在播放的回放，nil 表示正常游戏
+	playbackFrame int            // 回放播放到的帧
+	savedRules    *sim.Rules     // 开始播放回放前玩家的规则设置，播放结束后恢复
+	replays       []storedReplay // 回放列表中的回放
+	replayChoice  int            // 回放列表当前选项
+	ghost         *ghost         // 同种子最佳成绩的幽灵潜艇
+
+	// 成就相关
+	achievementStorage AchievementStorage
+	unlocked           map[string]int64 // 已解锁的成就及解锁时间
+	achievementsFailed bool             // 成就存档读取失败，不再保存以免覆盖
+	run                achievementRun   // 本局的成就判定状态
+	toasts             []string         // 等待显示的成就解锁提示
+	achievementChoice  int              // 成就列表当前选项
+
+	// 累计统计相关
+	statsStorage StatsStorage
+	stats        Stats
+	statsFailed  bool  // 统计存档读取失败，不再保存以免覆盖
+	runStats     Stats // 本局的统计，结束时计入 stats
+	statsChoice  int   // 统计界面当前行
+
+	// 排行榜相关
+	leaderboardSize int // 每种模式排行榜显示的记录数，存储中保留全部记录
+	highScorePage   int // 排行榜当前页
 }
 
 const (
@@ -170,9 +248,39 @@ func NewGame() *Game {
 	ebiten.SetWindowTitle("Rush Out the Tunnel")
 
 	highScoreStorage = NewHighScoreStorage()
-	g := &Game{}
-	_ = g.loadHighScores() // 启动时加载排行榜
-	g.reset()              // reset is called first
+	bombs := sim.DefaultBombRules()
+	g := &Game{
+		input:              NewEbitenInput(),
+		replayStorage:      NewReplayStorage(),
+		courseStorage:      NewCourseStorage(),
+		achievementStorage: NewAchievementStorage(),
+		statsStorage:       NewStatsStorage(),
+		leaderboardSize:    DefaultLeaderboardSize,
+		difficulty:         sim.Normal,
+		difficultyChoice:   1, // Normal
+		bombRules:          &bombs,
+		blastRadius:        sim.DefaultBlastRadius,
+	}
+	_ = g.SetCustomDifficulty(sim.Normal)
+	// 启动时加载排行榜，存档损坏时提示玩家
+	switch err := g.loadHighScores(); {
+	case errors.Is(err, ErrHighScoresRecovered):
+		g.showMessage("Scores restored", 180)
+	case err != nil:
+		log.Printf("Failed to load high scores: %v", err)
+		g.showMessage("Scores lost!", 180)
+	}
+	if err := g.loadAchievements(); err != nil {
+		// 本次运行解锁的成就只保留在内存中
+		log.Printf("Failed to load achievements: %v", err)
+		g.unlocked = make(map[string]int64)
+		g.achievementsFailed = true
+	}
+	if err := g.loadStats(); err != nil {
+		log.Printf("Failed to load stats: %v", err)
+		g.statsFailed = true
+	}
+	g.reset() // reset is called first
 	g.state = StateTitle
 	// Buttons are initialized once, not on every reset
 	g.menuButtonRects = []image.Rectangle{
@@ -180,6 +288,8 @@ func NewGame() *Game {
 		image.Rect(122, 23, 122+34, 23+9), // Help
 		image.Rect(122, 38, 122+34, 38+9), // About
 		image.Rect(122, 53, 122+34, 53+9), // Exit
+		moreButtonRect,                    // More
+		replaysButtonRect,                 // Replays
 	}
 
 	// 初始化名字输入字符网格
@@ -250,29 +360,52 @@ func drawHandDrawnText(screen *ebiten.Image, str string, x, y int, clr color.Col
 	}
 }
 
+// SetInputSource 替换输入源，可用于测试、机器人或远程控制
+func (g *Game) SetInputSource(src InputSource) {
+	g.input = src
+}
+
+// SetSeed 指定之后每局游戏使用的随机种子，用于复现问题或同种子挑战
+func (g *Game) SetSeed(seed int64) {
+	g.fixedSeed = seed
+	g.hasFixedSeed = true
+}
+
+// ClearSeed 取消指定的种子，之后每局重新随机选择种子
+func (g *Game) ClearSeed() {
+	g.hasFixedSeed = false
+}
+
+// Seed 返回当前局使用的随机种子
+func (g *Game) Seed() int64 {
+	return g.seed
+}
+
+// chooseSeed 选择本局的随机种子并初始化随机数发生器
+func (g *Game) chooseSeed() {
+	if g.hasFixedSeed {
+		g.useSeed(g.fixedSeed)
+	} else {
+		g.useSeed(time.Now().UnixNano() % maxSeed)
+	}
+}
+
+// useSeed 使用指定种子初始化随机数发生器
+func (g *Game) useSeed(seed int64) {
+	g.seed = seed
+	g.rng = rand.New(rand.NewSource(g.seed))
+	log.Printf("Game seed: %d", g.seed)
+}
+
 func (g *Game) reset() {
-	g.player = &Player{
-		x:  screenWidth / 4,
-		y:  screenHeight / 2,
-		vy: 0,
-	}
-	g.tunnels = []*Tunnel{}
-	g.collectibles = []*Collectible{}
-	g.distance = 0
-	g.score = 0
-	g.bombs = 3
-	g.isBombing = false
-	g.bombTimer = 0
+	g.chooseSeed()
+	g.sim = sim.New(g.seed, g.rules())
+	g.recording = sim.NewReplay(g.seed, g.rules())
+	g.playback = nil
+	g.loadGhost()
+	g.startAchievementRun()
+	g.runStats = Stats{}
 	g.countdownTimer = 180 // 3 seconds at 60 FPS
-	g.tunnelHeight = 50
-	g.tunnelTopY = 15
-	g.slope = 0
-	g.player.y = g.tunnelTopY + g.tunnelHeight/2
-
-	// 新增：初始化原版道具生成变量
-	g.nextItem = (rand.Intn(5) + 1) * 32
-	g.thisItem = 0
-	g.itemPt = 0
 
 	// Define button position and size
 	buttonSize := 40
@@ -299,52 +432,78 @@ func (g *Game) reset() {
 	g.nameInputPosition = 0
 }
 
-func (g *Game) spawnTunnel(x float64) {
-	g.tunnels = append(g.tunnels, &Tunnel{
-		x:      x,
-		topY:   g.tunnelTopY,
-		height: g.tunnelHeight,
-		width:  10,
-	})
+// rules 返回当前选择对应的模拟规则
+func (g *Game) rules() sim.Rules {
+	rules := sim.DefaultRules()
+	rules.Mode = g.mode
+	rules.Difficulty = g.difficulty
+	rules.Course = g.course
+	rules.Generator = g.generator
+	rules.Collision = g.collision
+	rules.Items = g.items
+	rules.Bombs = g.bombRules
+	rules.Obstacles = g.obstacles
+	rules.Enemies = g.enemies
+	rules.Blast = g.blastRadius
+	rules.Scoring = g.scoring
+	rules.Lives = g.lives
+	rules.PowerUps = g.powerUps
+	if g.collision == sim.CollisionMask {
+		rules.Masks = GetResourceManager().CollisionMasks()
+	}
+	return rules
+}
+
+// applyRules 把规则写回之后各局使用的设置，与 rules 相对
+func (g *Game) applyRules(rules sim.Rules) {
+	g.mode = rules.Mode
+	g.difficulty = rules.Difficulty
+	g.course = rules.Course
+	g.generator = rules.Generator
+	g.collision = rules.Collision
+	g.items = rules.Items
+	g.bombRules = rules.Bombs
+	g.obstacles = rules.Obstacles
+	g.enemies = rules.Enemies
+	g.blastRadius = rules.Blast
+	g.scoring = rules.Scoring
+	g.lives = rules.Lives
+	g.powerUps = rules.PowerUps
+}
+
+// startNewGame 以指定模式开始新游戏，先选择难度
+func (g *Game) startNewGame(mode sim.Mode) {
+	g.mode = mode
+	g.course = nil
+	g.state = StateDifficulty
 }
 
 // updateTitle 处理标题界面输入与菜单选择
 func (g *Game) updateTitle() error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
-		g.menuChoice = (g.menuChoice + 1) % 5
+	if g.in.IsPressed(ActionDown) {
+		g.menuChoice = (g.menuChoice + 1) % len(g.menuButtonRects)
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+	if g.in.IsPressed(ActionUp) {
 		g.menuChoice--
 		if g.menuChoice < 0 {
-			g.menuChoice = 4
+			g.menuChoice = len(g.menuButtonRects) - 1
 		}
 	}
-	// Check for mouse click on menu items
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		for i, r := range g.menuButtonRects {
-			if isMouseInRect(r) {
-				g.menuChoice = i
-				if err := g.selectMenuItem(); err != nil {
-					return err
-				}
-			}
-		}
-	}
-	// Check for touch click on menu items
+	// Check for mouse or touch click on menu items
 	for i, r := range g.menuButtonRects {
-		if isTouchInRect(r) {
+		if g.in.TapIn(r) {
 			g.menuChoice = i
 			if err := g.selectMenuItem(); err != nil {
 				return err
 			}
 		}
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+	if g.in.IsPressed(ActionConfirm) {
 		if err := g.selectMenuItem(); err != nil {
 			return err
 		}
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if g.in.IsPressed(ActionBack) {
 		g.state = StateExitConfirm
 		return nil
 	}
@@ -379,7 +538,7 @@ func (g *Game) updateGame() error {
 
 // handlePauseInput 检查并处理暂停输入
 func (g *Game) handlePauseInput() bool {
-	if inpututil.IsKeyJustPressed(ebiten.KeyZ) {
+	if g.in.IsPressed(ActionPause) {
 		g.state = StatePause
 		g.showMessage("Paused", 60)
 		return true
@@ -389,7 +548,7 @@ func (g *Game) handlePauseInput() bool {
 
 // handleExitInput 检查并处理退出输入
 func (g *Game) handleExitInput() bool {
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if g.in.IsPressed(ActionBack) {
 		g.state = StateExitConfirm
 		return true
 	}
@@ -398,9 +557,7 @@ func (g *Game) handleExitInput() bool {
 
 // updateHelp 处理帮助界面输入
 func (g *Game) updateHelp() error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
-		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
-		len(inpututil.AppendJustPressedTouchIDs(nil)) > 0 {
+	if g.in.IsPressed(ActionConfirm) || g.in.Tapped() {
 		g.state = StateTitle
 	}
 	return nil
@@ -408,9 +565,7 @@ func (g *Game) updateHelp() error {
 
 // updateAbout 处理关于界面输入
 func (g *Game) updateAbout() error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
-		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
-		len(inpututil.AppendJustPressedTouchIDs(nil)) > 0 {
+	if g.in.IsPressed(ActionConfirm) || g.in.Tapped() {
 		g.state = StateTitle
 	}
 	return nil
@@ -418,10 +573,8 @@ func (g *Game) updateAbout() error {
 
 // updateWin 处理胜利界面输入
 func (g *Game) updateWin() error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
-		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
-		len(inpututil.AppendJustPressedTouchIDs(nil)) > 0 {
-		g.state = StateTitle
+	if g.in.IsPressed(ActionConfirm) || g.in.Tapped() {
+		g.state = StateResults
 	}
 	return nil
 }
@@ -449,13 +602,13 @@ func (g *Game) pressEnd(x, y int) bool {
 
 	g.endBoxHighlightTimer = 8
 	if len(g.nameInput) > 0 {
-		g.insertHighScore(g.nameInput, g.score)
+		rank := g.insertHighScore(g.nameInput, g.sim.Score())
 		g.saveHighScores()
-		g.state = StateHighScores
+		g.openHighScoresAt(StateHighScores, rank)
 	} else {
-		g.insertHighScore("Player", g.score)
+		rank := g.insertHighScore("Player", g.sim.Score())
 		g.saveHighScores()
-		g.state = StateHighScores
+		g.openHighScoresAt(StateHighScores, rank)
 	}
 
 	return true
@@ -464,8 +617,7 @@ func (g *Game) pressEnd(x, y int) bool {
 // updateNameInput 处理玩家名字输入 - 基于原版GetName实现
 func (g *Game) updateNameInput() error {
 	g.handleNameInputNavigation()
-	g.handleNameInputMouseClick()
-	g.handleNameInputTouch()
+	g.handleNameInputTaps()
 	g.handleNameInputEnter()
 	g.handleNameInputBackspace()
 	g.handleNameInputEnd()
@@ -475,19 +627,19 @@ func (g *Game) updateNameInput() error {
 
 // handleNameInputNavigation 处理方向键导航
 func (g *Game) handleNameInputNavigation() {
-	if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+	if g.in.IsPressed(ActionUp) {
 		g.nameInputCursorY--
 		if g.nameInputCursorY < 0 {
 			g.nameInputCursorY = 4
 		}
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+	if g.in.IsPressed(ActionDown) {
 		g.nameInputCursorY++
 		if g.nameInputCursorY > 4 {
 			g.nameInputCursorY = 0
 		}
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+	if g.in.IsPressed(ActionLeft) {
 		g.nameInputCursorX--
 		if g.nameInputCursorY == 4 {
 			if g.nameInputCursorX < 0 {
@@ -499,7 +651,7 @@ func (g *Game) handleNameInputNavigation() {
 			}
 		}
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+	if g.in.IsPressed(ActionRight) {
 		g.nameInputCursorX++
 		if g.nameInputCursorY == 4 {
 			if g.nameInputCursorX > 10 {
@@ -513,59 +665,54 @@ func (g *Game) handleNameInputNavigation() {
 	}
 }
 
-// handleNameInputMouseClick 处理鼠标点击
-func (g *Game) handleNameInputMouseClick() {
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		x, y := ebiten.CursorPosition()
-		if g.pressBackspace(x, y) {
-			return
-		}
-		if g.pressEnd(x, y) {
-			return
-		}
-		g.handleNameInputRect(isMouseInRect)
-	}
-}
-
-// handleNameInputTouch 处理触摸输入
-func (g *Game) handleNameInputTouch() {
-	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
-		x, y := ebiten.TouchPosition(id)
-		if x >= 110 && x < 150 && y >= 50 && y < 58 {
-			g.pressBackspace(x, y)
-			return
-		}
-		if x >= 110 && x < 150 && y >= 68 && y < 76 {
-			g.pressEnd(x, y)
-			return
+// handleNameInputTaps 处理鼠标点击和触摸输入
+func (g *Game) handleNameInputTaps() {
+	for _, p := range g.in.Taps {
+		x, y := p.X, p.Y
+		if p.Touch {
+			if x >= 110 && x < 150 && y >= 50 && y < 58 {
+				g.pressBackspace(x, y)
+				return
+			}
+			if x >= 110 && x < 150 && y >= 68 && y < 76 {
+				g.pressEnd(x, y)
+				return
+			}
+		} else {
+			if g.pressBackspace(x, y) {
+				return
+			}
+			if g.pressEnd(x, y) {
+				return
+			}
 		}
-		g.handleNameInputRect(isTouchInRect)
+		g.handleNameInputRect(p.Point)
 	}
 }
 
 // handleNameInputEnter 处理字符输入（Enter键）
 func (g *Game) handleNameInputEnter() {
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+	if g.in.IsPressed(ActionConfirm) {
 		g.inputSelectedChar()
 	}
 }
 
 // handleNameInputBackspace 处理删除（Backspace键）
 func (g *Game) handleNameInputBackspace() {
-	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
+	if g.in.IsPressed(ActionErase) {
 		g.pressBackspace(120, 40)
 	}
 }
 
 // handleNameInputEnd 处理确认输入（空格键结束）
 func (g *Game) handleNameInputEnd() {
-	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+	if g.in.IsPressed(ActionEnd) {
 		g.pressEnd(120, 60)
 	}
 }
 
 // handleNameInputRect 处理名字输入界面的点击事件
-func (g *Game) handleNameInputRect(inRect func(r image.Rectangle) bool) {
+func (g *Game) handleNameInputRect(p image.Point) {
 	// 检查是否点击了字符网格
 	for gridY := 0; gridY < 5; gridY++ {
 		for gridX := 0; gridX < 13; gridX++ {
@@ -574,7 +721,7 @@ func (g *Game) handleNameInputRect(inRect func(r image.Rectangle) bool) {
 				continue
 			}
 
-			if inRect(g.nameInputGridRects[gridY][gridX]) {
+			if p.In(g.nameInputGridRects[gridY][gridX]) {
 				g.nameInputCursorX = gridX
 				g.nameInputCursorY = gridY
 				g.inputSelectedChar()
@@ -598,13 +745,13 @@ func (g *Game) inputSelectedChar() {
 			// 如果是空格键，结束输入
 			if char == " " {
 				if len(g.nameInput) > 0 {
-					g.insertHighScore(g.nameInput, g.score)
+					rank := g.insertHighScore(g.nameInput, g.sim.Score())
 					g.saveHighScores()
-					g.state = StateHighScores
+					g.openHighScoresAt(StateHighScores, rank)
 				} else {
-					g.insertHighScore("Player", g.score)
+					rank := g.insertHighScore("Player", g.sim.Score())
 					g.saveHighScores()
-					g.state = StateHighScores
+					g.openHighScoresAt(StateHighScores, rank)
 				}
 				return
 			}
@@ -623,7 +770,7 @@ func (g *Game) inputSelectedChar() {
 
 // updatePause 处理暂停界面输入
 func (g *Game) updatePause() error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyZ) {
+	if g.in.IsPressed(ActionPause) {
 		g.state = StateGame
 		g.showMessage("Resume", 60)
 	}
@@ -633,7 +780,7 @@ func (g *Game) updatePause() error {
 
 // updateExitConfirm 处理退出确认界面输入
 func (g *Game) updateExitConfirm() error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyY) {
+	if g.in.IsPressed(ActionYes) {
 		// 设置退出标志而不是直接返回 ebiten.Termination
 		// 在 Android 中，ebiten.Termination 不会关闭应用
 		// 需要通过 MainActivity 来处理退出
@@ -641,8 +788,8 @@ func (g *Game) updateExitConfirm() error {
 
 		return nil
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyN) ||
-		inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if g.in.IsPressed(ActionNo | ActionBack) {
+		g.endPlayback()
 		g.state = StateTitle
 	}
 
@@ -651,9 +798,10 @@ func (g *Game) updateExitConfirm() error {
 
 // updateHighScores 处理高分榜界面输入
 func (g *Game) updateHighScores() error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
-		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
-		len(inpututil.AppendJustPressedTouchIDs(nil)) > 0 {
+	if g.updateHighScorePage() {
+		return nil
+	}
+	if g.in.IsPressed(ActionConfirm) || g.in.Tapped() {
 		g.state = StateTitle
 	}
 
@@ -670,26 +818,28 @@ func (g *Game) updateGameOver() error {
 
 		return nil
 	}
-	if g.isHighScore(g.score) {
-		g.nameInput = ""
-		g.nameInputCursorX = 0
-		g.nameInputCursorY = 0
-		g.nameInputPosition = 0
-		g.state = StateNameInput
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
-		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
-		len(inpututil.AppendJustPressedTouchIDs(nil)) > 0 {
-		g.state = StateTitle
+	if g.in.IsPressed(ActionConfirm) || g.in.Tapped() {
+		g.state = StateResults
 	}
 
 	return nil
 }
 
+// startNameInput 进入新纪录的名字输入界面
+func (g *Game) startNameInput() {
+	g.nameInput = ""
+	g.nameInputCursorX = 0
+	g.nameInputCursorY = 0
+	g.nameInputPosition = 0
+	g.state = StateNameInput
+}
+
 // updateHighScoresThenGame 处理高分榜后自动进入游戏
 func (g *Game) updateHighScoresThenGame() error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
-		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
-		len(inpututil.AppendJustPressedTouchIDs(nil)) > 0 {
+	if g.updateHighScorePage() {
+		return nil
+	}
+	if g.in.IsPressed(ActionConfirm) || g.in.Tapped() {
 		g.reset()
 		g.state = StateCountdown
 		return nil
@@ -698,298 +848,54 @@ func (g *Game) updateHighScoresThenGame() error {
 	return nil
 }
 
-// updateGameLogic 保留原有 updateGame 的主逻辑部分
+// updateGameLogic 推进一帧模拟并处理产生的事件
 func (g *Game) updateGameLogic() {
-	// 1. 炸弹状态递减与爆炸效果
-	if g.isBombing {
-		g.updateBombState()
-		return
-	}
-
-	// 2. 炸弹触发
-	g.tryTriggerBomb()
-	if g.isBombing {
-		return
-	}
-
-	// 3. 距离、分数、胜利判定
-	g.updateDistanceAndScore()
-	if g.state == StateWin {
-		return
-	}
-
-	// 4. 隧道坡度与高度调整
-	g.updateTunnelSlopeAndHeight()
-
-	// 5. 隧道生成与移动
-	g.spawnTunnelIfNeeded()
-	g.moveTunnels()
-	g.removeOffscreenTunnels()
-
-	// 6. 道具生成与移动
-	if g.shouldSpawnCollectible() {
-		g.spawnCollectible()
-	}
-	g.moveCollectibles()
-	g.removeOffscreenCollectibles()
-
-	// 7. 玩家操作与物理
-	isUp := g.isPressingUp()
-	g.updatePlayerVelocity(isUp)
-	g.clampPlayerVelocity()
-	g.updatePlayerPosition()
-
-	// 8. 碰撞检测
-	if g.checkPlayerBoundaryCollision() {
-		g.state = StateGameOver
-		return
-	}
-	if g.checkPlayerTunnelCollision() {
-		g.state = StateGameOver
+	if g.playbackExhausted() {
+		g.endPlayback()
+		g.showMessage("Replay end", 60)
+		g.state = StateTitle
 		return
 	}
-	g.checkPlayerCollectibleCollision()
-
-	// 9. 消息提示
-	g.updateTipMessage()
-}
-
-// isPressingBomb 检查当前是否有炸弹触发输入（键盘、鼠标、触摸）
-func (g *Game) isPressingBomb() bool {
-	if inpututil.IsKeyJustPressed(ebiten.KeyX) {
-		return true
-	}
-
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		x, y := ebiten.CursorPosition()
-		if g.bombButtonRect.Min.X <= x && x < g.bombButtonRect.Max.X && g.bombButtonRect.Min.Y <= y && y < g.bombButtonRect.Max.Y {
-			return true
-		}
-	}
-
-	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
-		x, y := ebiten.TouchPosition(id)
-		if g.bombButtonRect.Min.X <= x && x < g.bombButtonRect.Max.X && g.bombButtonRect.Min.Y <= y && y < g.bombButtonRect.Max.Y {
-			return true
-		}
-	}
-
-	return false
-}
-
-// isPressingUp 检查当前是否有上升输入（键盘、鼠标、触摸）
-func (g *Game) isPressingUp() bool {
-	if ebiten.IsKeyPressed(ebiten.KeyUp) {
-		return true
-	}
 
-	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-		x, y := ebiten.CursorPosition()
-		if g.upButtonRect.Min.X <= x && x < g.upButtonRect.Max.X && g.upButtonRect.Min.Y <= y && y < g.upButtonRect.Max.Y {
-			return true
-		}
-	}
-
-	for _, id := range ebiten.TouchIDs() {
-		x, y := ebiten.TouchPosition(id)
-		if g.upButtonRect.Min.X <= x && x < g.upButtonRect.Max.X && g.upButtonRect.Min.Y <= y && y < g.upButtonRect.Max.Y {
-			return true
-		}
+	in := g.nextSimInput()
+	for _, e := range g.sim.Step(in) {
+		g.trackAchievementEvent(e)
+		g.trackStatsEvent(e)
+		g.handleSimEvent(e)
 	}
-
-	return false
-}
-
-// updateBombState 处理炸弹状态递减、爆炸效果（清空隧道和道具）
-func (g *Game) updateBombState() {
-	g.bombTimer--
-	if g.bombTimer <= 0 {
-		g.isBombing = false
-		g.tunnels = []*Tunnel{}
-		g.collectibles = []*Collectible{}
+	g.checkAchievements()
+	if g.ghost != nil {
+		g.ghost.step()
 	}
-}
 
-// tryTriggerBomb 检查是否满足触发炸弹条件，若满足则消耗炸弹并进入爆炸状态
-func (g *Game) tryTriggerBomb() {
-	if g.isPressingBomb() && g.bombs > 0 && !g.isBombing {
-		g.bombs--
-		g.isBombing = true
-		g.bombTimer = 15
+	// 消息提示
+	if g.state == StateGame && !g.sim.Bombing() {
+		g.updateTipMessage()
 	}
 }
 
-// updateDistanceAndScore 距离递增、分数递增，胜利判定
-func (g *Game) updateDistanceAndScore() {
-	g.distance++
-	if g.distance%40 == 0 {
-		g.score++
-	}
-	if g.distance >= 4000 {
+// handleSimEvent 处理模拟事件：显示消息、切换界面状态
+func (g *Game) handleSimEvent(e sim.Event) {
+	switch e.Kind {
+	case sim.EventCoin:
+		g.showMessage("获得金币！", 30)
+	case sim.EventItem:
+		g.showMessage(itemMessages[e.Item], 45)
+	case sim.EventLifeLost:
+		g.showMessage(fmt.Sprintf("Lives left: %d", g.sim.Lives()), 45)
+	case sim.EventShieldHit:
+		g.showMessage("Shield broke!", 45)
+	case sim.EventCrash:
+		g.finishRecording()
+		g.state = StateGameOver
+	case sim.EventWin:
+		g.finishRecording()
 		g.winAnimFrame = 0
 		g.showMessage("Win", 60)
 		g.state = StateWin
 	}
 }
 
-// updateTunnelSlopeAndHeight 隧道坡度、顶部高度、隧道高度的动态调整
-func (g *Game) updateTunnelSlopeAndHeight() {
-	if g.distance%10 == 0 {
-		g.slope = rand.Intn(3)
-	}
-	if g.distance%200 == 0 && g.tunnelHeight > 20 {
-		g.tunnelHeight--
-	}
-	if g.slope == 0 && g.tunnelTopY > 10 {
-		g.tunnelTopY--
-	}
-	if g.slope == 2 && g.tunnelTopY < screenHeight-g.tunnelHeight-10 {
-		g.tunnelTopY++
-	}
-}
-
-// spawnTunnelIfNeeded 判断是否需要生成新隧道段并生成
-func (g *Game) spawnTunnelIfNeeded() {
-	// 每帧在屏幕右侧生成新的隧道段
-	g.spawnTunnel(159)
-}
-
-// moveTunnels 所有隧道段左移
-func (g *Game) moveTunnels() {
-	for _, t := range g.tunnels {
-		t.x -= 1.0
-	}
-}
-
-// removeOffscreenTunnels 移除超出屏幕的隧道段
-func (g *Game) removeOffscreenTunnels() {
-	remaining := g.tunnels[:0]
-	for _, t := range g.tunnels {
-		if t.x+t.width > 0 {
-			remaining = append(remaining, t)
-		}
-	}
-	g.tunnels = remaining
-}
-
-// shouldSpawnCollectible 判断当前帧是否应生成新道具
-func (g *Game) shouldSpawnCollectible() bool {
-	if g.distance > 3840 {
-		return false
-	}
-	return g.distance-g.thisItem == g.nextItem
-}
-
-// spawnCollectible 生成新道具（如金币）
-func (g *Game) spawnCollectible() {
-	// 检查是否有空闲的道具槽位
-	hasEmptySlot := false
-	for _, c := range g.collectibles {
-		if c == nil {
-			hasEmptySlot = true
-			break
-		}
-	}
-	if !hasEmptySlot && len(g.collectibles) < 5 {
-		hasEmptySlot = true
-	}
-	if hasEmptySlot {
-		rm := GetResourceManager()
-		coinImage := rm.GetResource(ResourceCoin)
-		if coinImage != nil {
-			coinY := g.tunnelTopY + float64(rand.Intn(int(g.tunnelHeight)-10))
-			g.collectibles = append(g.collectibles, &Collectible{
-				image: coinImage,
-				x:     157,
-				y:     coinY,
-				w:     coinImage.Bounds().Dx(),
-				h:     coinImage.Bounds().Dy(),
-			})
-		}
-	}
-	g.thisItem = g.distance
-	g.nextItem = (rand.Intn(5) + 1) * 32
-}
-
-// moveCollectibles 所有道具左移
-func (g *Game) moveCollectibles() {
-	for _, c := range g.collectibles {
-		c.x -= 1.0
-	}
-}
-
-// removeOffscreenCollectibles 移除超出屏幕的道具
-func (g *Game) removeOffscreenCollectibles() {
-	remaining := g.collectibles[:0]
-	for _, c := range g.collectibles {
-		if c.x+float64(c.w) > 0 {
-			remaining = append(remaining, c)
-		}
-	}
-	g.collectibles = remaining
-}
-
-// updatePlayerVelocity 根据输入更新玩家速度
-func (g *Game) updatePlayerVelocity(isUp bool) {
-	if isUp {
-		g.player.vy -= 0.2
-	} else {
-		g.player.vy += 0.1
-	}
-}
-
-// clampPlayerVelocity 限制玩家速度在合理范围
-func (g *Game) clampPlayerVelocity() {
-	if g.player.vy > 1.0 {
-		g.player.vy = 1.0
-	}
-	if g.player.vy < -1.0 {
-		g.player.vy = -1.0
-	}
-}
-
-// updatePlayerPosition 根据速度更新玩家位置
-func (g *Game) updatePlayerPosition() {
-	g.player.y += g.player.vy
-}
-
-// checkPlayerBoundaryCollision 检查玩家是否撞到上下边界
-func (g *Game) checkPlayerBoundaryCollision() bool {
-	return g.player.y < 0 || int(g.player.y)+4 > screenHeight
-}
-
-// checkPlayerTunnelCollision 检查玩家是否撞到隧道
-func (g *Game) checkPlayerTunnelCollision() bool {
-	playerRect := image.Rect(int(g.player.x), int(g.player.y), int(g.player.x)+8, int(g.player.y)+4)
-	for _, t := range g.tunnels {
-		topRect := image.Rect(int(t.x), 0, int(t.x+t.width), int(t.topY))
-		bottomRect := image.Rect(int(t.x), int(t.topY+t.height), int(t.x+t.width), screenHeight)
-		if playerRect.Overlaps(topRect) || playerRect.Overlaps(bottomRect) {
-			return true
-		}
-	}
-	return false
-}
-
-// checkPlayerCollectibleCollision 检查玩家是否吃到道具，处理分数和消息
-func (g *Game) checkPlayerCollectibleCollision() (collected bool) {
-	playerRect := image.Rect(int(g.player.x), int(g.player.y), int(g.player.x)+8, int(g.player.y)+4)
-	remaining := g.collectibles[:0]
-	collected = false
-	for _, c := range g.collectibles {
-		collectibleRect := image.Rect(int(c.x), int(c.y), int(c.x)+c.w, int(c.y)+c.h)
-		if playerRect.Overlaps(collectibleRect) {
-			g.score += 5
-			g.showMessage("获得金币！", 30)
-			collected = true
-			continue
-		}
-		remaining = append(remaining, c)
-	}
-	g.collectibles = remaining
-	return
-}
-
 // updateTipMessage 定时显示提示消息
 func (g *Game) updateTipMessage() {
 	g.updateTipTimer()
@@ -998,8 +904,7 @@ func (g *Game) updateTipMessage() {
 func (g *Game) selectMenuItem() error {
 	switch g.menuChoice {
 	case 0: // New
-		g.reset()
-		g.state = StateHighScoresThenGame
+		g.startNewGame(sim.ModeClassic)
 	case 1: // Help
 		g.state = StateHelp
 	case 2: // About
@@ -1008,11 +913,19 @@ func (g *Game) selectMenuItem() error {
 		// 设置退出标志而不是直接返回 ebiten.Termination
 		SetExitFlag(true)
 		return nil
+	case 4: // More
+		g.moreChoice = 0
+		g.state = StateMore
+	case 5: // Replays
+		g.openReplays()
 	}
 	return nil
 }
 
 func (g *Game) Update() error {
+	g.in = g.input.Poll()
+	g.updateToasts()
+
 	switch g.state {
 	case StateTitle:
 		return g.updateTitle()
@@ -1038,6 +951,22 @@ func (g *Game) Update() error {
 		return g.updateGameOver()
 	case StateHighScoresThenGame:
 		return g.updateHighScoresThenGame()
+	case StateMore:
+		return g.updateMore()
+	case StateDifficulty:
+		return g.updateDifficulty()
+	case StateCourses:
+		return g.updateCourses()
+	case StateEditor:
+		return g.updateEditor()
+	case StateResults:
+		return g.updateResults()
+	case StateAchievements:
+		return g.updateAchievements()
+	case StateStats:
+		return g.updateStats()
+	case StateReplays:
+		return g.updateReplays()
 	}
 	return nil
 }
@@ -1066,6 +995,22 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		g.drawExitConfirm(screen)
 	case StateHighScores, StateHighScoresThenGame:
 		g.drawHighScores(screen)
+	case StateMore:
+		g.drawMore(screen)
+	case StateDifficulty:
+		g.drawDifficulty(screen)
+	case StateCourses:
+		g.drawCourses(screen)
+	case StateEditor:
+		g.drawEditor(screen)
+	case StateResults:
+		g.drawResults(screen)
+	case StateAchievements:
+		g.drawAchievements(screen)
+	case StateStats:
+		g.drawStats(screen)
+	case StateReplays:
+		g.drawReplays(screen)
 	}
 
 	// 消息提示统一绘制
@@ -1078,25 +1023,51 @@ func (g *Game) Draw(screen *ebiten.Image) {
 func (g *Game) drawHighScores(screen *ebiten.Image) {
 	screen.Fill(color.White)
 
-	// 显示当前高分榜
-	title := "TOP 5 SCORES"
-	drawHandDrawnText(screen, title, 30, 10, color.RGBA{0, 0, 0, 255})
-
-	for i, hs := range highScores {
+	// 显示当前模式的高分榜
+	label := "SCORES"
+	if g.mode == sim.ModeEndless {
+		label = "ENDLESS"
+	}
+	title := fmt.Sprintf("TOP %d %s", g.leaderboardSize, label)
+	if key := g.ruleset(); key != "" {
+		// 非原版规则的排行榜显示规则标识，区分不同规则的成绩
+		title = fmt.Sprintf("%s #%s", label, key[:4])
+	}
+	drawHandDrawnText(screen, title, (screenWidth-len(title)*8)/2, 1, color.RGBA{0, 0, 0, 255})
+
+	// 当前页的记录，不足一页时用空行补齐
+	table := g.visibleHighScores()
+	own := g.ownRank()
+	for row := 0; row < leaderboardPageRows; row++ {
+		i := g.highScorePage*leaderboardPageRows + row
+		y := 12 + 11*row
+		var hs HighScore
+		if i < len(table) {
+			hs = table[i]
+		}
 		name := hs.Name
 		if name == "" {
 			name = "---"
 		}
 		colorName := color.RGBA{0, 0, 128, 255}
 		colorScore := color.RGBA{128, 0, 0, 255}
-		if g.score == hs.Score && g.nameInput != "" && name == g.nameInput {
+		if i == own {
 			colorName = color.RGBA{255, 0, 0, 255}
 			colorScore = color.RGBA{255, 0, 0, 255}
 		}
 		scoreStr := fmt.Sprintf("%d", hs.Score)
-		drawHandDrawnText(screen, fmt.Sprintf("%d. %s", i+1, name), 30, 20+11*i, colorName)
-		drawHandDrawnText(screen, scoreStr, 110, 20+11*i, colorScore)
+		drawHandDrawnText(screen, fmt.Sprintf("%3d %s", i+1, name), 10, y, colorName)
+		// 分数右对齐到难度标记之前，位数多时不会与标记重叠
+		drawHandDrawnText(screen, scoreStr, 148-len(scoreStr)*8, y, colorScore)
+		if hs.Name != "" {
+			drawHandDrawnText(screen, difficultyMark(hs.Difficulty), 150, y, color.RGBA{128, 128, 128, 255})
+		}
+		if hs.bombFree() {
+			drawHandDrawnText(screen, "*", 2, y, color.RGBA{0, 128, 0, 255})
+		}
 	}
+	g.drawOwnRank(screen)
+	g.drawLeaderboardPage(screen)
 }
 
 func (g *Game) drawTitle(screen *ebiten.Image) {
@@ -1111,15 +1082,16 @@ func (g *Game) drawTitle(screen *ebiten.Image) {
 		// 如果标题图像加载失败，显示文本标题
 		ebitenutil.DebugPrint(screen, "RUSH OUT THE TUNNEL\n\nAssets failed to load!\nTitle image is nil")
 	}
+	drawMoreButton(screen)
 
 	// Draw menu selector
-	selectorY := 8 + g.menuChoice*15
-	drawSelector(screen, image.Rect(122, selectorY, 122+34, selectorY+9), color.RGBA{R: 70, G: 130, B: 180, A: 128})
+	drawSelector(screen, g.menuButtonRects[g.menuChoice], color.RGBA{R: 70, G: 130, B: 180, A: 128})
 }
 
 func (g *Game) drawCountdown(screen *ebiten.Image) {
-	g.drawGameScene(screen)
-	g.drawGameHUD(screen)
+	snap := g.sim.Snapshot()
+	g.drawGameScene(screen, &snap)
+	g.drawGameHUD(screen, &snap)
 
 	countdownNum := int(math.Ceil(float64(g.countdownTimer) / 60.0))
 	if countdownNum > 0 {
@@ -1149,68 +1121,154 @@ func (g *Game) drawCountdown(screen *ebiten.Image) {
 	}
 }
 
-func (g *Game) drawGameScene(screen *ebiten.Image) {
+func (g *Game) drawGameScene(screen *ebiten.Image, snap *sim.Snapshot) {
 	screen.Fill(backgroundColor)
 
-	for _, t := range g.tunnels {
-		ebitenutil.DrawRect(screen, t.x, 0, t.width, t.topY, tunnelWallColor)
-		ebitenutil.DrawRect(screen, t.x, t.topY+t.height, t.width, screenHeight-(t.topY+t.height), tunnelWallColor)
+	for _, t := range snap.Tunnels {
+		ebitenutil.DrawRect(screen, t.X, 0, t.Width, t.TopY, tunnelWallColor)
+		ebitenutil.DrawRect(screen, t.X, t.TopY+t.Height, t.Width, screenHeight-(t.TopY+t.Height), tunnelWallColor)
 	}
 
 	// Draw Collectibles
-	for _, c := range g.collectibles {
-		if c.image != nil {
+	rm := GetResourceManager()
+	for _, c := range snap.Collectibles {
+		img := rm.GetResource(collectibleResources[c.Kind])
+		if img == nil {
+			continue
+		}
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(c.X, c.Y)
+		screen.DrawImage(img, op)
+	}
+
+	// Draw Obstacles
+	for _, o := range snap.Obstacles {
+		img := rm.GetResource(obstacleResources[o.Kind])
+		if img == nil {
+			continue
+		}
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(o.X, o.Y)
+		screen.DrawImage(img, op)
+	}
+
+	// Draw Enemies
+	for _, e := range snap.Enemies {
+		img := rm.GetResource(enemyResources[e.Kind])
+		if img == nil {
+			continue
+		}
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(e.X, e.Y)
+		screen.DrawImage(img, op)
+	}
+
+	// 飞行中的炸弹与爆炸范围
+	if p := snap.Projectile; p != nil {
+		if img := rm.GetResource(ResourceBomb); img != nil {
 			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(c.x, c.y)
-			screen.DrawImage(c.image, op)
+			op.GeoM.Translate(p.X-3, p.Y-1)
+			screen.DrawImage(img, op)
 		}
 	}
+	if b := snap.Blast; b != nil {
+		alpha := uint8(255 * b.Timer / sim.BlastTicks)
+		vector.DrawFilledCircle(screen, float32(b.X), float32(b.Y), float32(b.Radius), color.RGBA{alpha, alpha, alpha, alpha}, false)
+	}
 
-	// Draw Player
-	rm := GetResourceManager()
+	// Draw Ghost
+	g.drawGhost(screen)
+
+	// Draw Player，撞毁后等待复活时绘制爆炸
 	submarineImage := rm.GetResource(ResourceSubmarine)
-	if submarineImage != nil {
+	if snap.Respawn > 0 {
+		drawExplosion(screen, snap.Player, min(sim.RespawnTicks-snap.Respawn, 30))
+	} else if submarineImage != nil {
 		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(g.player.x, g.player.y)
+		op.GeoM.Translate(snap.Player.X, snap.Player.Y)
 		screen.DrawImage(submarineImage, op)
 	} else {
 		// 提供更详细的调试信息
 		ebitenutil.DebugPrint(screen, "Assets loading failed!\nSubmarine image is nil")
 		// 绘制一个简单的矩形作为玩家
-		ebitenutil.DrawRect(screen, g.player.x, g.player.y, 8, 4, color.RGBA{255, 255, 0, 255})
+		ebitenutil.DrawRect(screen, snap.Player.X, snap.Player.Y, sim.PlayerWidth, sim.PlayerHeight, color.RGBA{255, 255, 0, 255})
+	}
+
+	// 护盾：最后一秒闪烁提示即将消失；无敌期间快速闪烁
+	shield := snap.Player.PowerUps[sim.PowerShield]
+	if shield > 60 || shield/6%2 == 1 || snap.Invulnerable/3%2 == 1 {
+		cx := float32(snap.Player.X) + sim.PlayerWidth/2
+		cy := float32(snap.Player.Y) + sim.PlayerHeight/2
+		vector.StrokeCircle(screen, cx, cy, sim.PlayerWidth/2+2, 1, color.White, false)
 	}
 }
 
-func (g *Game) drawGameHUD(screen *ebiten.Image) {
+func (g *Game) drawGameHUD(screen *ebiten.Image, snap *sim.Snapshot) {
 	// Draw HUD text (score)
-	scoreText := fmt.Sprintf("SCORE: %d", g.score)
+	scoreText := fmt.Sprintf("SCORE: %d", snap.Score)
 	drawHandDrawnText(screen, scoreText, 5, 5, color.White)
+	if snap.Multiplier > 1 {
+		drawHandDrawnText(screen, fmt.Sprintf("x%d", snap.Multiplier), 9+len(scoreText)*8, 5, color.RGBA{255, 255, 0, 255})
+	}
+	if snap.NearMiss {
+		drawHandDrawnText(screen, "CLOSE", 5, 25, color.RGBA{255, 255, 0, 255})
+	}
+	g.drawGhostIndicator(screen, snap)
 
 	// Draw bombs
 	rm := GetResourceManager()
 	bombImage := rm.GetResource(ResourceBomb)
 	if bombImage != nil {
-		for i := 0; i < g.bombs; i++ {
+		for i := 0; i < snap.Bombs; i++ {
 			op := &ebiten.DrawImageOptions{}
 			op.GeoM.Translate(float64(screenWidth-15-i*8), 5)
 			screen.DrawImage(bombImage, op)
 		}
 	}
 
+	// 生效中的能力及剩余时间
+	for i, power := range snap.Player.ActivePowerUps() {
+		x := 60 + i*12
+		if img := rm.GetResource(collectibleResources[power.Spec().Kind]); img != nil {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(float64(x), 14)
+			screen.DrawImage(img, op)
+		}
+		left := float32(snap.Player.PowerUps[power]) / float32(power.Spec().Ticks)
+		vector.DrawFilledRect(screen, float32(x), 21, 8*left, 1, color.White, false)
+	}
+
+	// 剩余命数
+	if g.sim.Rules().Lives != nil {
+		if submarineImage := rm.GetResource(ResourceSubmarine); submarineImage != nil {
+			for i := 0; i < snap.Lives; i++ {
+				op := &ebiten.DrawImageOptions{}
+				op.GeoM.Translate(float64(screenWidth-17-i*10), 14)
+				screen.DrawImage(submarineImage, op)
+			}
+		}
+	}
+
 	// Draw the virtual up button
 	buttonColor := color.RGBA{100, 100, 100, 128} // Semi-transparent grey
 	drawButton(screen, g.upButtonRect, buttonColor, nil)
 
 	// Draw the virtual bomb button
 	drawButton(screen, g.bombButtonRect, buttonColor, bombImage)
+
+	// 回放标识
+	if g.isPlayingBack() {
+		drawHandDrawnText(screen, "REPLAY", 56, 70, color.RGBA{255, 0, 0, 255})
+	}
 }
 
 func (g *Game) drawGame(screen *ebiten.Image) {
-	g.drawGameScene(screen)
-	g.drawGameHUD(screen)
+	snap := g.sim.Snapshot()
+	g.drawGameScene(screen, &snap)
+	g.drawGameHUD(screen, &snap)
 
 	// Draw bomb flash effect
-	if g.isBombing {
+	if snap.Bombing {
 		screen.Fill(color.White)
 	}
 }
@@ -1222,36 +1280,74 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 // 排行榜读写
 func (g *Game) loadHighScores() error {
 	loaded, err := highScoreStorage.Load()
-	if err != nil {
+	if err != nil && !errors.Is(err, ErrHighScoresRecovered) {
 		return err
 	}
 
-	for i := range highScores {
-		if i < len(loaded) {
-			highScores[i] = loaded[i]
-		} else {
-			highScores[i] = HighScore{"", 0}
+	// 按模式分组，存储中的记录已按分数从高到低排列
+	highScores = [sim.NumModes][]HighScore{}
+	for _, hs := range loaded {
+		if hs.Mode < 0 || hs.Mode >= sim.NumModes {
+			continue
 		}
+		highScores[hs.Mode] = append(highScores[hs.Mode], hs)
 	}
-	return nil
+	return err
 }
 
 func (g *Game) saveHighScores() error {
-	return highScoreStorage.Save(highScores[:])
-}
+	var all []HighScore
+	for mode := range highScores {
+		all = append(all, highScores[mode]...)
+	}
+	return highScoreStorage.Save(all)
+}
+
+// insertHighScore 按分数插入排行榜，同分排在旧记录之后，返回在当前规则排行榜中的名次（从 0 开始）。
+// 新记录标记为玩家自己的记录，同一排行榜中之前的标记被清除。
+// 存储中保留全部记录，排行榜界面只显示前 leaderboardSize 条
+func (g *Game) insertHighScore(name string, score int) int {
+	key := g.ruleset()
+	table := highScores[g.mode]
+	for i := range table {
+		if table[i].Rules == key {
+			table[i].Own = false
+		}
+	}
+	i := sort.Search(len(table), func(i int) bool { return score > table[i].Score })
+	used := g.sim.BombsUsed()
+	table = slices.Insert(table, i, HighScore{
+		Name:       name,
+		Score:      score,
+		Mode:       g.mode,
+		Difficulty: g.difficulty.Name,
+		BombsUsed:  &used,
+		Time:       time.Now().Truncate(time.Second),
+		Distance:   g.sim.Distance(),
+		Coins:      g.sim.Coins(),
+		Seed:       g.sim.Seed(),
+		Version:    GameVersion,
+		Rules:      key,
+		Own:        true,
+	})
+	highScores[g.mode] = table
 
-func (g *Game) insertHighScore(name string, score int) {
-	for i := range highScores {
-		if score > highScores[i].Score {
-			copy(highScores[i+1:], highScores[i:len(highScores)-1])
-			highScores[i] = HighScore{name, score}
-			return
+	rank := 0
+	for _, hs := range table[:i] {
+		if hs.Rules == key {
+			rank++
 		}
 	}
+	return rank
 }
 
+// isHighScore 检查分数能否进入当前模式、当前规则排行榜显示的名次
 func (g *Game) isHighScore(score int) bool {
-	return score > highScores[len(highScores)-1].Score
+	table := g.boardScores()
+	if len(table) < g.leaderboardSize {
+		return score > 0
+	}
+	return score > table[g.leaderboardSize-1].Score
 }
 
 // showMessage 显示消息
@@ -1276,7 +1372,7 @@ func (g *Game) showTip(tip string, duration int) {
 func (g *Game) updateTipTimer() {
 	g.tipTimer++
 	if g.tipTimer%200 == 0 {
-		g.curTipIdx = rand.Intn(len(g.tips))
+		g.curTipIdx = g.rng.Intn(len(g.tips))
 		g.showTip(g.tips[g.curTipIdx], 60)
 	}
 }
@@ -1322,6 +1418,8 @@ func (g *Game) drawWin(screen *ebiten.Image) {
 	drawHandDrawnText(screen, msg[:letters], 50, 40, color.RGBA{0, 128, 0, 255})
 	if g.winAnimFrame < len(msg)*15 {
 		g.winAnimFrame++
+	} else if bonus := g.sim.BombBonus(); bonus > 0 {
+		drawHandDrawnText(screen, fmt.Sprintf("BOMB +%d", bonus), 46, 54, color.RGBA{128, 0, 0, 255})
 	}
 }
 
@@ -1329,18 +1427,7 @@ func (g *Game) drawWin(screen *ebiten.Image) {
 func (g *Game) drawGameOver(screen *ebiten.Image) {
 	screen.Fill(color.White)
 	if !g.explosionDone {
-		cx := int(g.player.x) + 4
-		cy := int(g.player.y) + 2
-		for r := 2; r < g.explosionFrame*2; r += 4 {
-			col := color.RGBA{uint8(255 - r*4), uint8(128 + r*2), 0, 255}
-			for a := 0.0; a < 2*math.Pi; a += 0.2 {
-				x := cx + int(float64(r)*math.Cos(a))
-				y := cy + int(float64(r)*math.Sin(a))
-				if x >= 0 && x < screenWidth && y >= 0 && y < screenHeight {
-					screen.Set(x, y, col)
-				}
-			}
-		}
+		drawExplosion(screen, g.sim.Player(), g.explosionFrame)
 		return
 	}
 	// 爆炸动画结束后显示gameover.png
@@ -1353,6 +1440,27 @@ func (g *Game) drawGameOver(screen *ebiten.Image) {
 		op.GeoM.Translate(float64((screenWidth-imgW)/2), float64((screenHeight-imgH)/2))
 		screen.DrawImage(gameoverImage, op)
 	}
+
+	// 显示本局种子，便于复现和分享
+	seedText := fmt.Sprintf("SEED %d", g.seed)
+	vector.DrawFilledRect(screen, 0, 70, float32(len(seedText)*8+4), 10, color.White, false)
+	drawHandDrawnText(screen, seedText, 2, 71, color.RGBA{128, 128, 128, 255})
+}
+
+// drawExplosion 以玩家为中心绘制第 frame 帧的爆炸圆环
+func drawExplosion(screen *ebiten.Image, player sim.Player, frame int) {
+	cx := int(player.X) + sim.PlayerWidth/2
+	cy := int(player.Y) + sim.PlayerHeight/2
+	for r := 2; r < frame*2; r += 4 {
+		col := color.RGBA{uint8(255 - r*4), uint8(128 + r*2), 0, 255}
+		for a := 0.0; a < 2*math.Pi; a += 0.2 {
+			x := cx + int(float64(r)*math.Cos(a))
+			y := cy + int(float64(r)*math.Sin(a))
+			if x >= 0 && x < screenWidth && y >= 0 && y < screenHeight {
+				screen.Set(x, y, col)
+			}
+		}
+	}
 }
 
 // DrawNameInput 绘制名字输入界面 - 基于原版GetName实现
@@ -1457,25 +1565,6 @@ func (g *Game) drawExitConfirm(screen *ebiten.Image) {
 	drawHandDrawnText(screen, "Exit game? Y/N", 40, 40, color.RGBA{255, 0, 0, 255})
 }
 
-// isMouseInRect 检查鼠标是否在指定矩形内
-func isMouseInRect(r image.Rectangle) bool {
-	x, y := ebiten.CursorPosition()
-
-	return (image.Point{x, y}).In(r)
-}
-
-// isTouchInRect 检查是否有触摸点在指定矩形内
-func isTouchInRect(r image.Rectangle) bool {
-	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
-		x, y := ebiten.TouchPosition(id)
-		if (image.Point{x, y}).In(r) {
-			return true
-		}
-	}
-
-	return false
-}
-
 // drawButton 绘制按钮（带背景色和可选图标）
 func drawButton(screen *ebiten.Image, rect image.Rectangle, bgColor color.Color, icon *ebiten.Image) {
 	// 绘制背景
@@ -0,0 +1,118 @@
+//go:build !js
+
+package rush
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// backupPath 返回存档的备份文件路径
+func backupPath(path string) string {
+	return path + ".bak"
+}
+
+// writeFileAtomic 先写入临时文件再改名替换 path，写到一半被中断时原文件不受影响。
+// 原文件改名为备份，每次保存轮换一次。
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(path, backupPath(path)); err != nil && !os.IsNotExist(err) {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readFileWithBackup 读取 path 并用 decode 解码，文件缺失或损坏时改用备份。
+// 从备份恢复后，损坏的文件改名为 .corrupt 保留，备份内容写回 path。
+// 两者都不存在时返回零值；recovered 表示数据来自备份。
+func readFileWithBackup[T any](path string, decode func([]byte) (T, error)) (v T, recovered bool, err error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if v, err = decode(data); err == nil {
+			return v, false, nil
+		}
+	}
+	mainErr := err
+
+	backup, err := os.ReadFile(backupPath(path))
+	if err != nil {
+		if os.IsNotExist(mainErr) {
+			// 从未保存过
+			return v, false, nil
+		}
+		return v, false, mainErr
+	}
+	if v, err = decode(backup); err != nil {
+		return v, false, mainErr
+	}
+	if !os.IsNotExist(mainErr) {
+		os.Rename(path, path+".corrupt")
+	}
+	_ = writeFileAtomic(path, backup)
+	return v, true, nil
+}
+
+// saveJSONFile 以 JSON 编码 v 并原子地保存到 path
+func saveJSONFile(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// loadJSONFile 读取 path 中的 JSON，损坏时从备份恢复，两者都不存在时返回零值
+func loadJSONFile[T any](path string) (T, error) {
+	v, _, err := readFileWithBackup(path, func(data []byte) (v T, err error) {
+		err = json.Unmarshal(data, &v)
+		return v, err
+	})
+	return v, err
+}
+
+// saveHighScoreFile 以当前格式原子地保存排行榜文件
+func saveHighScoreFile(path string, highScores []HighScore) error {
+	data, err := marshalHighScores(highScores)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// loadHighScoreFile 读取排行榜文件，必要时从备份恢复，旧格式读取后以新格式写回
+func loadHighScoreFile(path string) ([]HighScore, error) {
+	var migrated bool
+	loaded, recovered, err := readFileWithBackup(path, func(data []byte) (hs []HighScore, err error) {
+		hs, migrated, err = unmarshalHighScores(data)
+		return hs, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if migrated {
+		_ = saveHighScoreFile(path, loaded)
+	}
+	if recovered {
+		return loaded, ErrHighScoresRecovered
+	}
+	return loaded, nil
+}
@@ -0,0 +1,157 @@
+//go:build !js
+
+package rush
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// corruptChecksum 改坏排行榜文件的校验和
+func corruptChecksum(t *testing.T, path string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file highScoreFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	file.Checksum = "00"
+	if data, err = json.Marshal(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// anyErr 在测试表中表示期望任意非空错误
+var anyErr = errors.New("any error")
+
+// mustSave 保存排行榜文件，失败时结束测试
+func mustSave(t *testing.T, path string, highScores []HighScore) {
+	t.Helper()
+	if err := saveHighScoreFile(path, highScores); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadHighScoreFile(t *testing.T) {
+	older := []HighScore{{Name: "OLD", Score: 100}}
+	newer := []HighScore{{Name: "NEW", Score: 200}}
+
+	tests := []struct {
+		name      string
+		setup     func(t *testing.T, path string)
+		want      []HighScore
+		err       error // 期望的错误，anyErr 表示任意错误
+		corrupted bool  // 是否应把损坏的文件保留为 .corrupt
+	}{
+		{
+			name:  "no files",
+			setup: func(t *testing.T, path string) {},
+		},
+		{
+			name: "good file",
+			setup: func(t *testing.T, path string) {
+				mustSave(t, path, older)
+				mustSave(t, path, newer)
+			},
+			want: newer,
+		},
+		{
+			name: "bad checksum with good backup",
+			setup: func(t *testing.T, path string) {
+				mustSave(t, path, older)
+				mustSave(t, path, newer)
+				corruptChecksum(t, path)
+			},
+			want:      older,
+			err:       ErrHighScoresRecovered,
+			corrupted: true,
+		},
+		{
+			name: "both corrupt",
+			setup: func(t *testing.T, path string) {
+				mustSave(t, path, older)
+				mustSave(t, path, newer)
+				corruptChecksum(t, path)
+				corruptChecksum(t, backupPath(path))
+			},
+			err: anyErr,
+		},
+		{
+			name: "missing primary",
+			setup: func(t *testing.T, path string) {
+				mustSave(t, path, older)
+				mustSave(t, path, newer)
+				if err := os.Remove(path); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: older,
+			err:  ErrHighScoresRecovered,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "highscores.json")
+			tt.setup(t, path)
+
+			got, err := loadHighScoreFile(path)
+			switch {
+			case tt.err == anyErr:
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+			case !errors.Is(err, tt.err):
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scores = %+v, want %+v", got, tt.want)
+			}
+			if _, err := os.Stat(path + ".corrupt"); (err == nil) != tt.corrupted {
+				t.Errorf(".corrupt exists = %v, want %v", err == nil, tt.corrupted)
+			}
+			if tt.err != ErrHighScoresRecovered {
+				return
+			}
+			// 恢复后主文件应已写回备份的内容
+			again, err := loadHighScoreFile(path)
+			if err != nil {
+				t.Fatalf("reload after recovery: %v", err)
+			}
+			if !reflect.DeepEqual(again, tt.want) {
+				t.Errorf("reloaded scores = %+v, want %+v", again, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadJSONFileRecoversBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "achievements.json")
+	want := []AchievementRecord{{ID: "first", Time: 1}}
+	if err := saveJSONFile(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveJSONFile(path, []AchievementRecord{{ID: "second", Time: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadJSONFile[[]AchievementRecord](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded %v, want the backup %v", got, want)
+	}
+}
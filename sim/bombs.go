@@ -0,0 +1,163 @@
+package sim
+
+import (
+	"errors"
+	"image"
+	"math"
+)
+
+// BombRules 炸弹的数量、补给与奖励规则
+type BombRules struct {
+	Start          int `json:"start"`           // 开局炸弹数
+	Cap            int `json:"cap"`             // 炸弹数上限
+	RefillInterval int `json:"refill_interval"` // 每隔多少距离生成一个炸弹补给，0 为不生成
+	UnusedBonus    int `json:"unused_bonus"`    // 胜利时每个剩余炸弹的奖励分
+}
+
+// originalBombRules 原版规则：开局 3 个炸弹，无法补给
+var originalBombRules = BombRules{Start: 3, Cap: maxBombs}
+
+// DefaultBombRules 返回默认的炸弹规则
+func DefaultBombRules() BombRules {
+	return BombRules{
+		Start:          3,
+		Cap:            maxBombs,
+		RefillInterval: 1000,
+		UnusedBonus:    10,
+	}
+}
+
+// Validate 检查炸弹规则是否合理
+func (b BombRules) Validate() error {
+	switch {
+	case b.Start < 0 || b.Cap < b.Start:
+		return errors.New("bomb start must be between 0 and cap")
+	case b.Cap > 9:
+		return errors.New("bomb cap must be at most 9")
+	case b.RefillInterval < 0 || b.UnusedBonus < 0:
+		return errors.New("bomb refill_interval and unused_bonus must not be negative")
+	}
+	return nil
+}
+
+const (
+	DefaultBlastRadius = 18 // 默认的炸弹爆炸半径
+	BlastTicks         = 12 // 爆炸效果显示的帧数
+
+	projectileSpeed = 3  // 炸弹每帧飞行的像素，相对屏幕
+	projectileFuse  = 20 // 炸弹没有撞到东西时飞行多少帧后爆炸
+	projectileReach = 3  // 障碍物或敌人进入炸弹多少像素内时引爆
+)
+
+// Projectile 从潜艇发射、飞行中的炸弹
+type Projectile struct {
+	X, Y float64
+	Fuse int // 剩余飞行帧数
+}
+
+// Blast 炸弹的爆炸范围，用于显示爆炸效果
+type Blast struct {
+	X, Y   float64
+	Radius int
+	Timer  int // 爆炸效果剩余帧数
+}
+
+// launchProjectile 从潜艇前端发射炸弹
+func (s *Simulation) launchProjectile() {
+	s.projectile = &Projectile{
+		X:    s.player.X + PlayerWidth,
+		Y:    s.player.Y + PlayerHeight/2,
+		Fuse: projectileFuse,
+	}
+}
+
+// updateProjectile 移动飞行中的炸弹，撞到墙壁、障碍物、敌人或飞出屏幕时爆炸
+func (s *Simulation) updateProjectile() {
+	if s.blast != nil {
+		s.blast.Timer--
+		if s.blast.Timer <= 0 {
+			s.blast = nil
+		}
+	}
+	p := s.projectile
+	if p == nil {
+		return
+	}
+	p.X += projectileSpeed
+	p.Fuse--
+	if p.Fuse <= 0 || p.X >= ScreenWidth || s.projectileHits(p) {
+		s.projectile = nil
+		s.detonate(p.X, p.Y)
+	}
+}
+
+// projectileHits 检查炸弹是否撞到墙壁，或接近障碍物、敌人
+func (s *Simulation) projectileHits(p *Projectile) bool {
+	reach := image.Rect(int(p.X), int(p.Y), int(p.X)+1, int(p.Y)+1).Inset(-projectileReach)
+	for _, t := range s.tunnels {
+		if p.X >= t.X && p.X < t.X+t.Width && (p.Y < t.TopY || p.Y >= t.TopY+t.Height) {
+			return true
+		}
+	}
+	for _, o := range s.obstacles {
+		if reach.Overlaps(image.Rect(int(o.X), int(o.Y), int(o.X)+o.W, int(o.Y)+o.H)) {
+			return true
+		}
+	}
+	for _, e := range s.enemies {
+		if reach.Overlaps(image.Rect(int(e.X), int(e.Y), int(e.X)+e.W, int(e.Y)+e.H)) {
+			return true
+		}
+	}
+	return false
+}
+
+// detonate 在指定位置爆炸：炸开半径内的隧道墙壁，摧毁范围内的障碍物、敌人和道具
+func (s *Simulation) detonate(x, y float64) {
+	r := float64(s.rules.Blast)
+	s.blast = &Blast{X: x, Y: y, Radius: s.rules.Blast, Timer: BlastTicks}
+	s.emit(EventBlast, x, y)
+
+	for _, t := range s.tunnels {
+		dx := distanceToSpan(x, t.X, t.X+t.Width)
+		if dx >= r {
+			continue
+		}
+		dy := math.Sqrt(r*r - dx*dx)
+		top := math.Max(math.Min(t.TopY, y-dy), 0)
+		bottom := math.Min(math.Max(t.TopY+t.Height, y+dy), ScreenHeight)
+		t.TopY, t.Height = top, bottom-top
+	}
+
+	inBlast := func(ox, oy float64, w, h int) bool {
+		dx := distanceToSpan(x, ox, ox+float64(w))
+		dy := distanceToSpan(y, oy, oy+float64(h))
+		return dx*dx+dy*dy < r*r
+	}
+	obstacles := s.obstacles[:0]
+	for _, o := range s.obstacles {
+		if !inBlast(o.X, o.Y, o.W, o.H) {
+			obstacles = append(obstacles, o)
+		}
+	}
+	s.obstacles = obstacles
+	enemies := s.enemies[:0]
+	for _, e := range s.enemies {
+		if !inBlast(e.X, e.Y, e.W, e.H) {
+			enemies = append(enemies, e)
+		}
+	}
+	s.enemies = enemies
+	collectibles := s.collectibles[:0]
+	for _, c := range s.collectibles {
+		if !inBlast(c.X, c.Y, c.W, c.H) {
+			collectibles = append(collectibles, c)
+		}
+	}
+	s.collectibles = collectibles
+}
+
+// distanceToSpan 返回 v 到区间 [lo, hi] 的距离，在区间内为 0
+func distanceToSpan(v, lo, hi float64) float64 {
+	return math.Max(math.Max(lo-v, v-hi), 0)
+}
@@ -0,0 +1,126 @@
+package sim
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// CollectibleKind 道具类型
+type CollectibleKind int
+
+const (
+	KindCoin       CollectibleKind = iota // 金币
+	KindDiamond                           // 钻石：高分
+	KindRedFish                           // 红鱼：中等分数
+	KindBombRefill                        // 炸弹补给：炸弹数加一
+	KindShield                            // 护盾：抵挡一次撞击
+	KindTimeSlow                          // 时间减缓：一段时间内隧道隔帧移动
+	KindMagnet                            // 磁铁：一段时间内吸引附近的道具
+
+	NumCollectibleKinds = iota // 道具类型数量
+)
+
+const maxBombs = 5 // 原版规则下炸弹补给的上限
+
+// CollectibleSpec 道具类型的参数
+type CollectibleSpec struct {
+	Name   string
+	W, H   int // 尺寸，与精灵一致
+	Weight int // 生成权重
+	Score  int // 吃到时的得分
+}
+
+var collectibleSpecs = [NumCollectibleKinds]CollectibleSpec{
+	KindCoin:       {Name: "coin", W: CoinWidth, H: CoinHeight, Weight: 60, Score: 5},
+	KindDiamond:    {Name: "diamond", W: 5, H: 5, Weight: 4, Score: 25},
+	KindRedFish:    {Name: "redfish", W: 6, H: 4, Weight: 14, Score: 10},
+	KindBombRefill: {Name: "bomb", W: 6, H: 3, Weight: 8},
+	KindShield:     {Name: "shield", W: 5, H: 5, Weight: 7},
+	KindTimeSlow:   {Name: "timeslow", W: 5, H: 5, Weight: 7},
+	KindMagnet:     {Name: "magnet", W: 5, H: 5, Weight: 7},
+}
+
+// Spec 返回道具类型的参数
+func (k CollectibleKind) Spec() CollectibleSpec {
+	if k < 0 || k >= NumCollectibleKinds {
+		return CollectibleSpec{}
+	}
+	return collectibleSpecs[k]
+}
+
+// String 返回道具类型名
+func (k CollectibleKind) String() string {
+	if name := k.Spec().Name; name != "" {
+		return name
+	}
+	return fmt.Sprintf("kind(%d)", int(k))
+}
+
+// ParseCollectibleKind 解析道具类型名，空字符串视为金币
+func ParseCollectibleKind(s string) (CollectibleKind, error) {
+	if s == "" {
+		return KindCoin, nil
+	}
+	for k, spec := range collectibleSpecs {
+		if spec.Name == s {
+			return CollectibleKind(k), nil
+		}
+	}
+	return KindCoin, fmt.Errorf("unknown collectible: %q", s)
+}
+
+// MarshalText 以类型名编码
+func (k CollectibleKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText 从类型名解码
+func (k *CollectibleKind) UnmarshalText(b []byte) error {
+	kind, err := ParseCollectibleKind(string(b))
+	if err != nil {
+		return err
+	}
+	*k = kind
+	return nil
+}
+
+// randomKind 按生成权重随机选择道具类型。
+// 钻石、红鱼和炸弹补给只在启用道具规则时出现，炸弹补给按固定间隔生成时不参与随机；
+// 护盾、时间减缓和磁铁只在启用能力规则时出现。
+func randomKind(rng *rand.Rand, rules Rules) CollectibleKind {
+	weight := func(k int) int {
+		switch CollectibleKind(k) {
+		case KindDiamond, KindRedFish:
+			if !rules.Items {
+				return 0
+			}
+		case KindBombRefill:
+			if !rules.Items || rules.Bombs != nil {
+				return 0
+			}
+		case KindShield, KindTimeSlow, KindMagnet:
+			if !rules.PowerUps {
+				return 0
+			}
+		}
+		return collectibleSpecs[k].Weight
+	}
+	total := 0
+	for k := range collectibleSpecs {
+		total += weight(k)
+	}
+	n := rng.Intn(total)
+	for k := range collectibleSpecs {
+		if n < weight(k) {
+			return CollectibleKind(k)
+		}
+		n -= weight(k)
+	}
+	return KindCoin
+}
+
+// newCollectible 在指定位置创建道具
+func newCollectible(kind CollectibleKind, x, y float64) Collectible {
+	spec := kind.Spec()
+	return Collectible{X: x, Y: y, W: spec.W, H: spec.H, Kind: kind}
+}
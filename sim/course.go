@@ -0,0 +1,289 @@
+package sim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+const (
+	// tunnelLookahead 隧道段从屏幕右侧生成到移动到玩家位置所需的距离
+	tunnelLookahead = 118
+	// coinLookahead 道具从屏幕右侧生成到移动到玩家位置所需的距离
+	coinLookahead = 116
+
+	// CourseOriginX 关卡中与当前距离相同的位置在屏幕上的X坐标，比玩家靠右 1 像素
+	CourseOriginX = playerStartX + 1
+
+	MinCourseHeight = PlayerHeight + 4 // 关卡中隧道允许的最小高度
+)
+
+// courseNamePattern 关卡名只允许用作文件名安全的字符
+var courseNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Course 手工设计的隧道关卡。
+// 距离均以玩家位置计算，关键帧之间线性插值，两端之外保持不变。
+type Course struct {
+	Name        string          `json:"name"`
+	WinDistance int             `json:"win_distance"`
+	Keyframes   []Keyframe      `json:"keyframes"`
+	Coins       []CoinSpawn     `json:"coins,omitempty"`
+	Obstacles   []ObstacleSpawn `json:"obstacles,omitempty"`
+	Enemies     []EnemySpawn    `json:"enemies,omitempty"`
+}
+
+// Keyframe 隧道形状的关键帧
+type Keyframe struct {
+	Distance int     `json:"distance"`
+	TopY     float64 `json:"top"`
+	Height   float64 `json:"height"`
+}
+
+// CoinSpawn 关卡中金币的位置
+type CoinSpawn struct {
+	Distance int             `json:"distance"`
+	Y        float64         `json:"y"`
+	Kind     CollectibleKind `json:"kind,omitempty"` // 道具类型，默认为金币
+}
+
+// NewCourse 创建一个平直隧道的空白关卡
+func NewCourse(name string) *Course {
+	return &Course{
+		Name:        name,
+		WinDistance: WinDistance / 2,
+		Keyframes: []Keyframe{
+			{Distance: 0, TopY: 15, Height: 50},
+			{Distance: WinDistance / 2, TopY: 15, Height: 50},
+		},
+	}
+}
+
+// ObstacleSpawn 关卡中障碍物的位置
+type ObstacleSpawn struct {
+	Distance int          `json:"distance"`
+	Y        float64      `json:"y"`
+	Kind     ObstacleKind `json:"kind"`
+}
+
+// EnemySpawn 关卡中敌人的位置和移动脚本
+type EnemySpawn struct {
+	Distance int          `json:"distance"`
+	Y        float64      `json:"y"`
+	Kind     EnemyKind    `json:"kind"`
+	Pattern  EnemyPattern `json:"pattern,omitempty"` // 默认为上下浮动
+}
+
+// ParseCourse 解析并校验 JSON 格式的关卡
+func ParseCourse(data []byte) (*Course, error) {
+	c := &Course{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	c.Normalize()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Marshal 将关卡编码为便于手工编辑的 JSON
+func (c *Course) Marshal() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// Normalize 按距离排序关键帧和金币
+func (c *Course) Normalize() {
+	sort.SliceStable(c.Keyframes, func(i, j int) bool {
+		return c.Keyframes[i].Distance < c.Keyframes[j].Distance
+	})
+	sort.SliceStable(c.Coins, func(i, j int) bool {
+		return c.Coins[i].Distance < c.Coins[j].Distance
+	})
+	sort.SliceStable(c.Obstacles, func(i, j int) bool {
+		return c.Obstacles[i].Distance < c.Obstacles[j].Distance
+	})
+	sort.SliceStable(c.Enemies, func(i, j int) bool {
+		return c.Enemies[i].Distance < c.Enemies[j].Distance
+	})
+}
+
+// Validate 检查关卡是否可玩
+func (c *Course) Validate() error {
+	if !courseNamePattern.MatchString(c.Name) {
+		return fmt.Errorf("invalid course name: %q", c.Name)
+	}
+	if c.WinDistance <= 0 {
+		return errors.New("course win_distance must be positive")
+	}
+	if len(c.Keyframes) == 0 {
+		return errors.New("course has no keyframes")
+	}
+	for i, k := range c.Keyframes {
+		if i > 0 && k.Distance <= c.Keyframes[i-1].Distance {
+			return fmt.Errorf("course keyframe %d: distances must be increasing", i)
+		}
+		if k.Height < MinCourseHeight || k.TopY < 0 || k.TopY+k.Height > ScreenHeight {
+			return fmt.Errorf("course keyframe %d: tunnel out of screen or too narrow", i)
+		}
+	}
+	for i, coin := range c.Coins {
+		if coin.Kind < 0 || coin.Kind >= NumCollectibleKinds {
+			return fmt.Errorf("course coin %d: unknown kind", i)
+		}
+		if coin.Y < 0 || coin.Y+float64(coin.Kind.Spec().H) > ScreenHeight {
+			return fmt.Errorf("course coin %d: out of screen", i)
+		}
+	}
+	for i, o := range c.Obstacles {
+		if o.Kind < 0 || o.Kind >= NumObstacleKinds {
+			return fmt.Errorf("course obstacle %d: unknown kind", i)
+		}
+		if o.Y < 0 || o.Y+float64(o.Kind.Spec().H) > ScreenHeight {
+			return fmt.Errorf("course obstacle %d: out of screen", i)
+		}
+	}
+	for i, e := range c.Enemies {
+		if e.Kind < 0 || e.Kind >= NumEnemyKinds {
+			return fmt.Errorf("course enemy %d: unknown kind", i)
+		}
+		if e.Pattern < 0 || e.Pattern >= NumEnemyPatterns {
+			return fmt.Errorf("course enemy %d: unknown pattern", i)
+		}
+		if e.Y < 0 || e.Y+float64(e.Kind.Spec().H) > ScreenHeight {
+			return fmt.Errorf("course enemy %d: out of screen", i)
+		}
+	}
+	return nil
+}
+
+// Clone 返回关卡的深拷贝
+func (c *Course) Clone() *Course {
+	clone := *c
+	clone.Keyframes = append([]Keyframe(nil), c.Keyframes...)
+	clone.Coins = append([]CoinSpawn(nil), c.Coins...)
+	clone.Obstacles = append([]ObstacleSpawn(nil), c.Obstacles...)
+	clone.Enemies = append([]EnemySpawn(nil), c.Enemies...)
+	return &clone
+}
+
+// From 返回从指定距离开始的关卡，用于从中途试玩
+func (c *Course) From(distance int) *Course {
+	from := c.Clone()
+	for i := range from.Keyframes {
+		from.Keyframes[i].Distance -= distance
+	}
+	from.Coins = from.Coins[:0]
+	for _, coin := range c.Coins {
+		if coin.Distance >= distance {
+			coin.Distance -= distance
+			from.Coins = append(from.Coins, coin)
+		}
+	}
+	from.Obstacles = from.Obstacles[:0]
+	for _, o := range c.Obstacles {
+		if o.Distance >= distance {
+			o.Distance -= distance
+			from.Obstacles = append(from.Obstacles, o)
+		}
+	}
+	from.Enemies = from.Enemies[:0]
+	for _, e := range c.Enemies {
+		if e.Distance >= distance {
+			e.Distance -= distance
+			from.Enemies = append(from.Enemies, e)
+		}
+	}
+	from.WinDistance = max(c.WinDistance-distance, 1)
+	return from
+}
+
+// ProfileAt 返回指定距离处隧道的顶部位置和高度
+func (c *Course) ProfileAt(distance int) (topY, height float64) {
+	ks := c.Keyframes
+	if distance <= ks[0].Distance {
+		return ks[0].TopY, ks[0].Height
+	}
+	last := ks[len(ks)-1]
+	if distance >= last.Distance {
+		return last.TopY, last.Height
+	}
+	i := sort.Search(len(ks), func(i int) bool { return ks[i].Distance > distance })
+	a, b := ks[i-1], ks[i]
+	t := float64(distance-a.Distance) / float64(b.Distance-a.Distance)
+	return a.TopY + (b.TopY-a.TopY)*t, a.Height + (b.Height-a.Height)*t
+}
+
+// equalCourse 比较两个关卡的内容是否相同
+func equalCourse(a, b *Course) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// courseGenerator 按关卡文件生成隧道和金币
+type courseGenerator struct {
+	course       *Course
+	nextCoin     int // 下一个待生成的关卡金币
+	nextObstacle int // 下一个待生成的关卡障碍物
+	nextEnemy    int // 下一个待生成的关卡敌人
+}
+
+func newCourseGenerator(c *Course) TunnelGenerator {
+	return &courseGenerator{course: c}
+}
+
+func (g *courseGenerator) Clone() TunnelGenerator {
+	c := *g
+	return &c
+}
+
+func (g *courseGenerator) Start() Column {
+	top, h := g.course.ProfileAt(0)
+	return Column{TopY: top, Height: h}
+}
+
+// Prefill 关卡开始时预先铺满屏幕内的隧道段
+func (g *courseGenerator) Prefill() []Tunnel {
+	var tunnels []Tunnel
+	for d := -playerStartX - 10; d < tunnelLookahead; d++ {
+		top, h := g.course.ProfileAt(d)
+		tunnels = append(tunnels, Tunnel{X: CourseOriginX + float64(d), TopY: top, Height: h, Width: 10})
+	}
+	return tunnels
+}
+
+func (g *courseGenerator) Next(distance int, rng *rand.Rand) (Column, []Collectible) {
+	top, h := g.course.ProfileAt(distance + tunnelLookahead)
+	var spawns []Collectible
+	coins := g.course.Coins
+	for ; g.nextCoin < len(coins) && coins[g.nextCoin].Distance <= distance+coinLookahead; g.nextCoin++ {
+		coin := coins[g.nextCoin]
+		spawns = append(spawns, newCollectible(coin.Kind, CourseOriginX+float64(coin.Distance-distance), coin.Y))
+	}
+	return Column{TopY: top, Height: h}, spawns
+}
+
+func (g *courseGenerator) Obstacles(distance int, col Column, rng *rand.Rand) []Obstacle {
+	var spawns []Obstacle
+	obstacles := g.course.Obstacles
+	for ; g.nextObstacle < len(obstacles) && obstacles[g.nextObstacle].Distance <= distance+tunnelLookahead; g.nextObstacle++ {
+		o := obstacles[g.nextObstacle]
+		spawns = append(spawns, newObstacle(o.Kind, CourseOriginX+float64(o.Distance-distance), o.Y))
+	}
+	return spawns
+}
+
+func (g *courseGenerator) Enemies(distance int, col Column, rng *rand.Rand) []Enemy {
+	var spawns []Enemy
+	enemies := g.course.Enemies
+	for ; g.nextEnemy < len(enemies) && enemies[g.nextEnemy].Distance <= distance+tunnelLookahead; g.nextEnemy++ {
+		e := enemies[g.nextEnemy]
+		spawns = append(spawns, newEnemy(e.Kind, e.Pattern, CourseOriginX+float64(e.Distance-distance), e.Y))
+	}
+	return spawns
+}
@@ -0,0 +1,83 @@
+package sim
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Difficulty 隧道生成与潜艇物理的参数
+type Difficulty struct {
+	Name            string  `json:"name"`
+	NarrowInterval  int     `json:"narrow_interval"`   // 每隔多少距离隧道收窄 1 像素
+	MinTunnelHeight float64 `json:"min_tunnel_height"` // 隧道收窄的下限
+	SlopeInterval   int     `json:"slope_interval"`    // 每隔多少距离随机改变一次坡度
+	Thrust          float64 `json:"thrust"`            // 按住上升时每帧的向上加速度
+	Gravity         float64 `json:"gravity"`           // 松开时每帧的向下加速度
+	MaxVelocity     float64 `json:"max_velocity"`      // 垂直速度上限
+}
+
+// 难度预设，Normal 与原版游戏一致
+var (
+	Easy = Difficulty{
+		Name:            "easy",
+		NarrowInterval:  300,
+		MinTunnelHeight: 28,
+		SlopeInterval:   14,
+		Thrust:          0.15,
+		Gravity:         0.08,
+		MaxVelocity:     0.8,
+	}
+	Normal = Difficulty{
+		Name:            "normal",
+		NarrowInterval:  200,
+		MinTunnelHeight: 20,
+		SlopeInterval:   10,
+		Thrust:          0.2,
+		Gravity:         0.1,
+		MaxVelocity:     1.0,
+	}
+	Hard = Difficulty{
+		Name:            "hard",
+		NarrowInterval:  120,
+		MinTunnelHeight: 16,
+		SlopeInterval:   6,
+		Thrust:          0.25,
+		Gravity:         0.13,
+		MaxVelocity:     1.3,
+	}
+)
+
+// CustomDifficultyName 自定义难度的名字
+const CustomDifficultyName = "custom"
+
+// Presets 返回所有难度预设
+func Presets() []Difficulty {
+	return []Difficulty{Easy, Normal, Hard}
+}
+
+// PresetByName 按名字查找难度预设
+func PresetByName(name string) (Difficulty, error) {
+	for _, d := range Presets() {
+		if d.Name == name {
+			return d, nil
+		}
+	}
+	return Difficulty{}, fmt.Errorf("unknown difficulty: %q", name)
+}
+
+// Validate 检查参数是否在可玩范围内
+func (d Difficulty) Validate() error {
+	switch {
+	case d.Name == "":
+		return errors.New("difficulty name is empty")
+	case d.NarrowInterval <= 0:
+		return errors.New("narrow_interval must be positive")
+	case d.SlopeInterval <= 0:
+		return errors.New("slope_interval must be positive")
+	case d.MinTunnelHeight < 12 || d.MinTunnelHeight > 50:
+		return errors.New("min_tunnel_height must be between 12 and 50")
+	case d.Thrust <= 0 || d.Gravity <= 0 || d.MaxVelocity <= 0:
+		return errors.New("thrust, gravity and max_velocity must be positive")
+	}
+	return nil
+}
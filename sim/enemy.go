@@ -0,0 +1,217 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// EnemyKind 敌人类型
+type EnemyKind int
+
+const (
+	EnemyFish      EnemyKind = iota // 鱼：追踪玩家或快速冲过隧道
+	EnemyJellyfish                  // 水母：上下漂浮
+
+	NumEnemyKinds = iota // 敌人类型数量
+)
+
+// EnemyPattern 敌人的移动脚本
+type EnemyPattern int
+
+const (
+	PatternSine  EnemyPattern = iota // 以生成位置为中心上下浮动
+	PatternChase                     // 缓慢向玩家所在高度靠拢
+	PatternDart                      // 水平方向比隧道更快地冲过来
+
+	NumEnemyPatterns = iota // 移动脚本数量
+)
+
+const (
+	enemyGrace       = 500 // 开局多少距离内不生成敌人
+	enemyMinInterval = 200 // 敌人之间的最小距离
+	enemyMaxInterval = 500 // 敌人之间的最大距离
+
+	sineAmplitude = 6    // 上下浮动的幅度
+	sinePeriod    = 60   // 上下浮动一个来回的帧数
+	chaseSpeed    = 0.4  // 追踪时每帧最多移动的像素
+	chaseRange    = 24   // 距离玩家多近时停止追踪，留出躲避的机会
+	dartSpeed     = 1.25 // 冲刺时在隧道移动之外每帧多移动的像素
+)
+
+// EnemySpec 敌人类型的参数
+type EnemySpec struct {
+	Name     string
+	W, H     int            // 尺寸，与精灵一致
+	Weight   int            // 生成权重
+	Patterns []EnemyPattern // 随机生成时可用的移动脚本
+}
+
+var enemySpecs = [NumEnemyKinds]EnemySpec{
+	EnemyFish:      {Name: "fish", W: 7, H: 4, Weight: 3, Patterns: []EnemyPattern{PatternChase, PatternDart}},
+	EnemyJellyfish: {Name: "jellyfish", W: 5, H: 6, Weight: 2, Patterns: []EnemyPattern{PatternSine}},
+}
+
+var patternNames = [NumEnemyPatterns]string{
+	PatternSine:  "sine",
+	PatternChase: "chase",
+	PatternDart:  "dart",
+}
+
+// Spec 返回敌人类型的参数
+func (k EnemyKind) Spec() EnemySpec {
+	if k < 0 || k >= NumEnemyKinds {
+		return EnemySpec{}
+	}
+	return enemySpecs[k]
+}
+
+// String 返回敌人类型名
+func (k EnemyKind) String() string {
+	if name := k.Spec().Name; name != "" {
+		return name
+	}
+	return fmt.Sprintf("enemy(%d)", int(k))
+}
+
+// ParseEnemyKind 解析敌人类型名
+func ParseEnemyKind(s string) (EnemyKind, error) {
+	for k, spec := range enemySpecs {
+		if spec.Name == s {
+			return EnemyKind(k), nil
+		}
+	}
+	return EnemyFish, fmt.Errorf("unknown enemy: %q", s)
+}
+
+// MarshalText 以类型名编码
+func (k EnemyKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText 从类型名解码
+func (k *EnemyKind) UnmarshalText(b []byte) error {
+	kind, err := ParseEnemyKind(string(b))
+	if err != nil {
+		return err
+	}
+	*k = kind
+	return nil
+}
+
+// String 返回移动脚本名
+func (p EnemyPattern) String() string {
+	if p >= 0 && p < NumEnemyPatterns {
+		return patternNames[p]
+	}
+	return fmt.Sprintf("pattern(%d)", int(p))
+}
+
+// ParseEnemyPattern 解析移动脚本名，空字符串视为上下浮动
+func ParseEnemyPattern(s string) (EnemyPattern, error) {
+	if s == "" {
+		return PatternSine, nil
+	}
+	for p, name := range patternNames {
+		if name == s {
+			return EnemyPattern(p), nil
+		}
+	}
+	return PatternSine, fmt.Errorf("unknown pattern: %q", s)
+}
+
+// MarshalText 以脚本名编码
+func (p EnemyPattern) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText 从脚本名解码
+func (p *EnemyPattern) UnmarshalText(b []byte) error {
+	pattern, err := ParseEnemyPattern(string(b))
+	if err != nil {
+		return err
+	}
+	*p = pattern
+	return nil
+}
+
+// Enemy 在隧道中移动的敌人，碰到即撞毁，可被炸弹消灭
+type Enemy struct {
+	X, Y    float64
+	W, H    int
+	Kind    EnemyKind
+	Pattern EnemyPattern
+	BaseY   float64 // 生成时的高度，上下浮动以此为中心
+	Age     int     // 生成后经过的帧数
+}
+
+// newEnemy 在指定位置创建敌人
+func newEnemy(kind EnemyKind, pattern EnemyPattern, x, y float64) Enemy {
+	spec := kind.Spec()
+	return Enemy{X: x, Y: y, W: spec.W, H: spec.H, Kind: kind, Pattern: pattern, BaseY: y}
+}
+
+// enemyScripts 各移动脚本每帧对敌人的操作，隧道的整体左移另外处理
+var enemyScripts = [NumEnemyPatterns]func(e *Enemy, p Player){
+	PatternSine: func(e *Enemy, p Player) {
+		e.Y = e.BaseY + sineAmplitude*math.Sin(float64(e.Age)*2*math.Pi/sinePeriod)
+	},
+	PatternChase: func(e *Enemy, p Player) {
+		if e.X < p.X+PlayerWidth+chaseRange {
+			return
+		}
+		target := p.Y + PlayerHeight/2 - float64(e.H)/2
+		e.Y += math.Max(-chaseSpeed, math.Min(target-e.Y, chaseSpeed))
+	},
+	PatternDart: func(e *Enemy, p Player) {
+		e.X -= dartSpeed
+	},
+}
+
+// EnemySpawner 可选接口，生成器实现后可以在隧道中放置敌人。
+// 每帧在 Next 之后调用，col 为本帧生成的隧道段。
+type EnemySpawner interface {
+	Enemies(distance int, col Column, rng *rand.Rand) []Enemy
+}
+
+// enemySchedule 随机生成敌人的节奏，可嵌入各生成器
+type enemySchedule struct {
+	next int // 下一个敌人的生成距离
+}
+
+// spawn 到达生成距离时在隧道段 col 中放置一个随机敌人，隧道太窄时跳过
+func (s *enemySchedule) spawn(distance int, col Column, rng *rand.Rand) []Enemy {
+	if s.next == 0 {
+		s.next = enemyGrace
+	}
+	if distance < s.next {
+		return nil
+	}
+	s.next = distance + enemyMinInterval + rng.Intn(enemyMaxInterval-enemyMinInterval)
+
+	kind := randomEnemyKind(rng)
+	spec := kind.Spec()
+	pattern := spec.Patterns[rng.Intn(len(spec.Patterns))]
+	span := int(col.Height) - spec.H - 2*sineAmplitude
+	if span < obstacleClearance {
+		return nil
+	}
+	y := col.TopY + sineAmplitude + float64(rng.Intn(span))
+	return []Enemy{newEnemy(kind, pattern, ScreenWidth-1, y)}
+}
+
+// randomEnemyKind 按生成权重随机选择敌人类型
+func randomEnemyKind(rng *rand.Rand) EnemyKind {
+	total := 0
+	for _, spec := range enemySpecs {
+		total += spec.Weight
+	}
+	n := rng.Intn(total)
+	for k, spec := range enemySpecs {
+		if n < spec.Weight {
+			return EnemyKind(k)
+		}
+		n -= spec.Weight
+	}
+	return EnemyFish
+}
@@ -0,0 +1,146 @@
+package sim
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// OriginalGenerator 原版游戏隧道生成器的名字
+const OriginalGenerator = "original"
+
+// Column 一段隧道的形状
+type Column struct {
+	TopY   float64
+	Height float64
+}
+
+// TunnelGenerator 隧道生成器，每局游戏创建一个新的实例
+type TunnelGenerator interface {
+	// Start 返回开局时的隧道形状，玩家从其中线出发
+	Start() Column
+	// Next 返回距离到达 distance 时在屏幕右侧生成的隧道段，以及同时生成的道具
+	Next(distance int, rng *rand.Rand) (Column, []Collectible)
+}
+
+// Prefiller 可选接口，开局时预先铺满屏幕内的隧道段
+type Prefiller interface {
+	Prefill() []Tunnel
+}
+
+// CollectibleLimiter 可选接口，生成器需要按屏幕内已有的道具数量决定是否生成道具时实现。
+// 每帧在 Next 之前调用
+type CollectibleLimiter interface {
+	SetOnScreen(n int)
+}
+
+// GeneratorFactory 按规则创建隧道生成器，rng 为本局的随机数发生器
+type GeneratorFactory func(rules Rules, rng *rand.Rand) TunnelGenerator
+
+var generators = map[string]GeneratorFactory{}
+
+// RegisterGenerator 注册隧道生成器，名字重复时 panic
+func RegisterGenerator(name string, factory GeneratorFactory) {
+	if _, dup := generators[name]; dup {
+		panic("sim: RegisterGenerator called twice for " + name)
+	}
+	generators[name] = factory
+}
+
+// GeneratorNames 返回所有已注册的隧道生成器名字，原版排在最前
+func GeneratorNames() []string {
+	names := make([]string, 0, len(generators))
+	for name := range generators {
+		if name != OriginalGenerator {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{OriginalGenerator}, names...)
+}
+
+// checkGenerator 检查生成器是否已注册
+func checkGenerator(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := generators[name]; !ok {
+		return fmt.Errorf("unknown tunnel generator: %q", name)
+	}
+	return nil
+}
+
+// newGenerator 创建本局的隧道生成器，关卡优先于规则中指定的生成器
+func newGenerator(rules Rules, rng *rand.Rand) TunnelGenerator {
+	if rules.Course != nil {
+		return newCourseGenerator(rules.Course)
+	}
+	factory, ok := generators[rules.generator()]
+	if !ok {
+		factory = generators[OriginalGenerator]
+	}
+	return factory(rules, rng)
+}
+
+// originalMaxCollectibles 原版屏幕内道具数量上限
+const originalMaxCollectibles = 5
+
+// coinSpawner 原版的道具生成节奏：每隔 32 的随机倍数在隧道内随机高度生成道具
+type coinSpawner struct {
+	rules      Rules
+	nextItem   int // 下一个道具生成距离
+	thisItem   int // 当前道具生成距离
+	lastRefill int // 上一个炸弹补给的生成距离
+	limit      int // 屏幕内道具达到该数量时跳过本次生成，0 为不限
+	onScreen   int // 屏幕内已有的道具数量
+}
+
+func newCoinSpawner(rules Rules, rng *rand.Rand) coinSpawner {
+	return coinSpawner{
+		rules:    rules,
+		nextItem: (rng.Intn(5) + 1) * 32,
+	}
+}
+
+// spawn 返回在隧道段 col 中生成的道具
+func (c *coinSpawner) spawn(distance int, col Column, rng *rand.Rand) []Collectible {
+	// 经典模式在终点前不再生成道具，无尽模式持续生成
+	if c.rules.hasWinDistance() && distance > 3840 {
+		return nil
+	}
+	if distance-c.thisItem != c.nextItem {
+		return nil
+	}
+	if c.limit > 0 && c.onScreen >= c.limit {
+		// 与原版相同，道具已满时跳过本次生成，不消耗生成位置的随机数
+		c.thisItem = distance
+		c.nextItem = (rng.Intn(5) + 1) * 32
+		return nil
+	}
+	span := max(int(col.Height)-10, 1)
+	y := col.TopY + float64(rng.Intn(span))
+	c.thisItem = distance
+	c.nextItem = (rng.Intn(5) + 1) * 32
+	kind := KindCoin
+	if interval := c.rules.bombRules().RefillInterval; interval > 0 && distance-c.lastRefill >= interval {
+		kind = KindBombRefill
+		c.lastRefill = distance
+	} else if c.rules.Items || c.rules.PowerUps {
+		kind = randomKind(rng, c.rules)
+	}
+	return []Collectible{newCollectible(kind, 157, y)}
+}
+
+// narrowing 按难度随距离逐渐收窄的隧道高度
+type narrowing struct {
+	rules  Rules
+	height float64
+}
+
+// next 返回距离为 distance 时的隧道高度
+func (n *narrowing) next(distance int) float64 {
+	if distance%n.rules.difficulty().NarrowInterval == 0 && n.height > n.rules.minTunnelHeight(distance) {
+		n.height--
+	}
+	return n.height
+}
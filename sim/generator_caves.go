@@ -0,0 +1,119 @@
+package sim
+
+import (
+	"math"
+	"math/rand"
+)
+
+func init() {
+	RegisterGenerator("sine", newSineGenerator)
+	RegisterGenerator("zigzag", newZigzagGenerator)
+}
+
+const (
+	caveMargin     = 10  // 隧道与屏幕上下边缘保持的最小距离
+	zigzagSlope    = 0.8 // 锯齿峡谷每帧升降的像素
+	zigzagTurnRate = 8   // 每隔多少个坡度间隔可能随机折返
+)
+
+// sineGenerator 平滑的正弦波洞穴：两条不同周期的正弦波叠加成隧道中线
+type sineGenerator struct {
+	rules          Rules
+	height         narrowing
+	phase1, phase2 float64
+	t              float64 // 波形进度，无尽模式下随距离加快
+	coins          coinSpawner
+	obstacles      obstacleSchedule
+	enemies        enemySchedule
+}
+
+func newSineGenerator(rules Rules, rng *rand.Rand) TunnelGenerator {
+	return &sineGenerator{
+		rules:  rules,
+		height: narrowing{rules: rules, height: 50},
+		phase1: rng.Float64() * 2 * math.Pi,
+		phase2: rng.Float64() * 2 * math.Pi,
+		coins:  newCoinSpawner(rules, rng),
+	}
+}
+
+func (g *sineGenerator) Clone() TunnelGenerator {
+	c := *g
+	return &c
+}
+
+func (g *sineGenerator) Start() Column {
+	return Column{TopY: 15, Height: 50}
+}
+
+func (g *sineGenerator) Next(distance int, rng *rand.Rand) (Column, []Collectible) {
+	h := g.height.next(distance)
+	g.t += g.rules.slopeStep(distance)
+	amp := math.Max((ScreenHeight-h)/2-caveMargin, 0)
+	amp *= math.Min(float64(distance)/200, 1) // 开局从平直逐渐过渡
+	wave := 0.6*math.Sin(g.t/90+g.phase1) + 0.4*math.Sin(g.t/37+g.phase2)
+	col := Column{TopY: ScreenHeight/2 - h/2 + amp*wave, Height: h}
+	return col, g.coins.spawn(distance, col, rng)
+}
+
+func (g *sineGenerator) Obstacles(distance int, col Column, rng *rand.Rand) []Obstacle {
+	return g.obstacles.spawn(distance, col, rng)
+}
+
+func (g *sineGenerator) Enemies(distance int, col Column, rng *rand.Rand) []Enemy {
+	return g.enemies.spawn(distance, col, rng)
+}
+
+// zigzagGenerator 锯齿峡谷：隧道以固定坡度上下折返
+type zigzagGenerator struct {
+	rules  Rules
+	col    Column
+	height narrowing
+	dir    float64 // 1 向下，-1 向上
+	coins  coinSpawner
+
+	obstacles obstacleSchedule
+	enemies   enemySchedule
+}
+
+func newZigzagGenerator(rules Rules, rng *rand.Rand) TunnelGenerator {
+	return &zigzagGenerator{
+		rules:  rules,
+		col:    Column{TopY: 15, Height: 50},
+		height: narrowing{rules: rules, height: 50},
+		dir:    1,
+		coins:  newCoinSpawner(rules, rng),
+	}
+}
+
+func (g *zigzagGenerator) Clone() TunnelGenerator {
+	c := *g
+	return &c
+}
+
+func (g *zigzagGenerator) Start() Column {
+	return g.col
+}
+
+func (g *zigzagGenerator) Next(distance int, rng *rand.Rand) (Column, []Collectible) {
+	if distance%(g.rules.difficulty().SlopeInterval*zigzagTurnRate) == 0 && rng.Intn(2) == 0 {
+		g.dir = -g.dir
+	}
+	g.col.Height = g.height.next(distance)
+	g.col.TopY += g.dir * zigzagSlope * g.rules.slopeStep(distance)
+	if lo := float64(caveMargin); g.col.TopY <= lo {
+		g.col.TopY, g.dir = lo, 1
+	}
+	if hi := ScreenHeight - g.col.Height - caveMargin; g.col.TopY >= hi {
+		g.col.TopY, g.dir = hi, -1
+	}
+	return g.col, g.coins.spawn(distance, g.col, rng)
+}
+
+func (g *zigzagGenerator) Obstacles(distance int, col Column, rng *rand.Rand) []Obstacle {
+	return g.obstacles.spawn(distance, col, rng)
+}
+
+func (g *zigzagGenerator) Enemies(distance int, col Column, rng *rand.Rand) []Enemy {
+	return g.enemies.spawn(distance, col, rng)
+}
@@ -0,0 +1,69 @@
+package sim
+
+import (
+	"math"
+	"math/rand"
+)
+
+func init() {
+	RegisterGenerator(OriginalGenerator, newOriginalGenerator)
+}
+
+// originalGenerator 原版游戏的隧道生成：随机在上升、水平、下降之间切换坡度
+type originalGenerator struct {
+	rules  Rules
+	col    Column
+	height narrowing
+	slope  int
+	coins  coinSpawner
+
+	obstacles obstacleSchedule
+	enemies   enemySchedule
+}
+
+func newOriginalGenerator(rules Rules, rng *rand.Rand) TunnelGenerator {
+	g := &originalGenerator{
+		rules:  rules,
+		col:    Column{TopY: 15, Height: 50},
+		height: narrowing{rules: rules, height: 50},
+		coins:  newCoinSpawner(rules, rng),
+	}
+	g.coins.limit = originalMaxCollectibles
+	return g
+}
+
+func (g *originalGenerator) Clone() TunnelGenerator {
+	c := *g
+	return &c
+}
+
+func (g *originalGenerator) Start() Column {
+	return g.col
+}
+
+func (g *originalGenerator) Next(distance int, rng *rand.Rand) (Column, []Collectible) {
+	if distance%g.rules.difficulty().SlopeInterval == 0 {
+		g.slope = rng.Intn(3)
+	}
+	g.col.Height = g.height.next(distance)
+	step := g.rules.slopeStep(distance)
+	if g.slope == 0 && g.col.TopY > 10 {
+		g.col.TopY = math.Max(g.col.TopY-step, 10)
+	}
+	if g.slope == 2 && g.col.TopY < ScreenHeight-g.col.Height-10 {
+		g.col.TopY = math.Min(g.col.TopY+step, ScreenHeight-g.col.Height-10)
+	}
+	return g.col, g.coins.spawn(distance, g.col, rng)
+}
+
+func (g *originalGenerator) SetOnScreen(n int) {
+	g.coins.onScreen = n
+}
+
+func (g *originalGenerator) Obstacles(distance int, col Column, rng *rand.Rand) []Obstacle {
+	return g.obstacles.spawn(distance, col, rng)
+}
+
+func (g *originalGenerator) Enemies(distance int, col Column, rng *rand.Rand) []Enemy {
+	return g.enemies.spawn(distance, col, rng)
+}
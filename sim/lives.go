@@ -0,0 +1,138 @@
+package sim
+
+import "errors"
+
+const (
+	RespawnTicks       = 45  // 撞毁后播放爆炸多少帧再复活
+	respawnInvulnTicks = 120 // 复活后无敌的帧数
+)
+
+// LivesRules 多条命与检查点规则
+type LivesRules struct {
+	Count      int `json:"count"`      // 命数，包括当前这条
+	Checkpoint int `json:"checkpoint"` // 每隔多少距离保存一个检查点
+}
+
+// DefaultLivesRules 返回默认的多条命规则
+func DefaultLivesRules() LivesRules {
+	return LivesRules{Count: 3, Checkpoint: 500}
+}
+
+// Validate 检查多条命规则是否合理
+func (l LivesRules) Validate() error {
+	switch {
+	case l.Count < 1 || l.Count > 9:
+		return errors.New("lives count must be between 1 and 9")
+	case l.Checkpoint <= 0:
+		return errors.New("lives checkpoint must be positive")
+	}
+	return nil
+}
+
+// GeneratorCloner 可选接口，生成器实现后检查点可以保存并恢复它的状态。
+// 未实现时复活后生成器从撞毁时的状态继续。
+type GeneratorCloner interface {
+	Clone() TunnelGenerator
+}
+
+// checkpoint 检查点保存的世界状态，复活时恢复
+type checkpoint struct {
+	distance     int
+	score        int
+	breakdown    ScoreBreakdown
+	bombs        int
+	player       Player
+	tunnels      []Tunnel
+	collectibles []Collectible
+	obstacles    []Obstacle
+	enemies      []Enemy
+	generator    TunnelGenerator
+	rngSeed      int64 // 保存检查点时随机数发生器重新播种使用的种子
+}
+
+// Lives 返回剩余的命数，未启用多条命规则时为 1
+func (s *Simulation) Lives() int {
+	return s.lives
+}
+
+// saveCheckpoint 保存当前的世界状态
+func (s *Simulation) saveCheckpoint() {
+	cp := &checkpoint{
+		distance:  s.distance,
+		score:     s.score,
+		breakdown: s.breakdown,
+		bombs:     s.bombs,
+		player:    s.player,
+		generator: s.generator,
+	}
+	if c, ok := s.generator.(GeneratorCloner); ok {
+		cp.generator = c.Clone()
+	}
+	// math/rand 无法复制状态，改为从当前状态取一个种子重新播种，复活时用同一种子恢复
+	cp.rngSeed = s.rng.Int63()
+	s.rng.Seed(cp.rngSeed)
+	cp.tunnels = copyAll(s.tunnels)
+	cp.collectibles = copyAll(s.collectibles)
+	cp.obstacles = copyAll(s.obstacles)
+	cp.enemies = copyAll(s.enemies)
+	s.checkpoint = cp
+}
+
+// loseLife 撞毁时扣一条命，还有命时开始复活倒计时，返回是否还能复活
+func (s *Simulation) loseLife(cause CrashCause) bool {
+	if s.checkpoint == nil || s.lives <= 1 {
+		s.lives = 0
+		return false
+	}
+	s.lives--
+	s.respawnTimer = RespawnTicks
+	s.emitCrash(EventLifeLost, cause)
+	return true
+}
+
+// respawn 恢复到上一个检查点，玩家短暂无敌
+func (s *Simulation) respawn() {
+	cp := s.checkpoint
+	s.distance = cp.distance
+	s.score = cp.score
+	s.breakdown = cp.breakdown
+	s.bombs = cp.bombs
+	s.player = cp.player
+	s.player.VY = 0
+	s.generator = cp.generator
+	if c, ok := cp.generator.(GeneratorCloner); ok {
+		s.generator = c.Clone()
+	}
+	s.rng.Seed(cp.rngSeed)
+	s.tunnels = pointersTo(cp.tunnels)
+	s.collectibles = pointersTo(cp.collectibles)
+	s.obstacles = pointersTo(cp.obstacles)
+	s.enemies = pointersTo(cp.enemies)
+
+	s.isBombing, s.bombTimer = false, 0
+	s.projectile, s.blast = nil, nil
+	s.player.PowerUps = [NumPowerUps]int{}
+	s.comboChain, s.comboLevel, s.comboTimer = 0, 0, 0
+	s.nearMissTimer = 0
+	s.invulnTimer = respawnInvulnTicks
+	s.emit(EventRespawn, s.player.X, s.player.Y)
+}
+
+// copyAll 复制指针切片指向的值
+func copyAll[T any](ps []*T) []T {
+	vs := make([]T, len(ps))
+	for i, p := range ps {
+		vs[i] = *p
+	}
+	return vs
+}
+
+// pointersTo 为每个值创建新的副本并返回指针切片
+func pointersTo[T any](vs []T) []*T {
+	ps := make([]*T, len(vs))
+	for i := range vs {
+		v := vs[i]
+		ps[i] = &v
+	}
+	return ps
+}
@@ -0,0 +1,178 @@
+package sim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+)
+
+// Collision 碰撞判定方式
+type Collision int
+
+const (
+	CollisionMask Collision = iota // 默认：按精灵不透明像素逐像素判定
+	CollisionRect                  // 原版：玩家为固定矩形，道具为整张图的范围
+)
+
+// String 返回碰撞判定方式的名字
+func (c Collision) String() string {
+	switch c {
+	case CollisionMask:
+		return "mask"
+	case CollisionRect:
+		return "rect"
+	default:
+		return fmt.Sprintf("collision(%d)", int(c))
+	}
+}
+
+// ParseCollision 解析碰撞判定方式，空字符串视为默认的逐像素判定
+func ParseCollision(s string) (Collision, error) {
+	switch s {
+	case "", "mask":
+		return CollisionMask, nil
+	case "rect":
+		return CollisionRect, nil
+	default:
+		return CollisionMask, fmt.Errorf("unknown collision: %q", s)
+	}
+}
+
+// MarshalText 以名字编码
+func (c Collision) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText 从名字解码
+func (c *Collision) UnmarshalText(b []byte) error {
+	collision, err := ParseCollision(string(b))
+	if err != nil {
+		return err
+	}
+	*c = collision
+	return nil
+}
+
+// Masks 逐像素碰撞使用的精灵掩码，未设置的使用实心矩形
+type Masks struct {
+	Player    *Mask                     `json:"player,omitempty"`
+	Coin      *Mask                     `json:"coin,omitempty"`
+	Items     map[CollectibleKind]*Mask `json:"items,omitempty"` // 金币以外的道具
+	Obstacles map[ObstacleKind]*Mask    `json:"obstacles,omitempty"`
+	Enemies   map[EnemyKind]*Mask       `json:"enemies,omitempty"`
+}
+
+// Mask 精灵的碰撞掩码，记录每个像素是否不透明
+type Mask struct {
+	w, h int
+	bits []bool
+}
+
+// NewMask 创建全透明的掩码
+func NewMask(w, h int) *Mask {
+	return &Mask{w: w, h: h, bits: make([]bool, w*h)}
+}
+
+// NewRectMask 创建实心矩形掩码
+func NewRectMask(w, h int) *Mask {
+	m := NewMask(w, h)
+	for i := range m.bits {
+		m.bits[i] = true
+	}
+	return m
+}
+
+// MaskFromImage 由图像的 alpha 通道生成掩码，alpha 超过一半视为不透明
+func MaskFromImage(img image.Image) *Mask {
+	b := img.Bounds()
+	m := NewMask(b.Dx(), b.Dy())
+	for y := 0; y < m.h; y++ {
+		for x := 0; x < m.w; x++ {
+			_, _, _, a := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			m.Set(x, y, a >= 0x8000)
+		}
+	}
+	return m
+}
+
+// Bounds 返回掩码的范围
+func (m *Mask) Bounds() image.Rectangle {
+	return image.Rect(0, 0, m.w, m.h)
+}
+
+// At 返回像素是否不透明，范围外视为透明
+func (m *Mask) At(x, y int) bool {
+	if x < 0 || y < 0 || x >= m.w || y >= m.h {
+		return false
+	}
+	return m.bits[y*m.w+x]
+}
+
+// Set 设置像素是否不透明
+func (m *Mask) Set(x, y int, opaque bool) {
+	m.bits[y*m.w+x] = opaque
+}
+
+// OverlapsRect 检查放置在 at 的掩码是否与矩形有不透明像素重叠
+func (m *Mask) OverlapsRect(at image.Point, r image.Rectangle) bool {
+	area := m.Bounds().Add(at).Intersect(r)
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if m.At(x-at.X, y-at.Y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Overlaps 检查放置在 at 的掩码与放置在 oAt 的掩码 o 是否有不透明像素重叠
+func (m *Mask) Overlaps(at image.Point, o *Mask, oAt image.Point) bool {
+	area := m.Bounds().Add(at).Intersect(o.Bounds().Add(oAt))
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if m.At(x-at.X, y-at.Y) && o.At(x-oAt.X, y-oAt.Y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MarshalJSON 按行编码为字符串数组，'#' 为不透明像素
+func (m *Mask) MarshalJSON() ([]byte, error) {
+	rows := make([]string, m.h)
+	for y := range rows {
+		row := make([]byte, m.w)
+		for x := range row {
+			row[x] = '.'
+			if m.At(x, y) {
+				row[x] = '#'
+			}
+		}
+		rows[y] = string(row)
+	}
+	return json.Marshal(rows)
+}
+
+// UnmarshalJSON 从 MarshalJSON 的格式解码
+func (m *Mask) UnmarshalJSON(b []byte) error {
+	var rows []string
+	if err := json.Unmarshal(b, &rows); err != nil {
+		return err
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return errors.New("empty mask")
+	}
+	*m = *NewMask(len(rows[0]), len(rows))
+	for y, row := range rows {
+		if len(row) != m.w {
+			return errors.New("mask rows differ in length")
+		}
+		for x := 0; x < m.w; x++ {
+			m.Set(x, y, row[x] == '#')
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package sim
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// ObstacleKind 障碍物类型
+type ObstacleKind int
+
+const (
+	ObstacleRock       ObstacleKind = iota // 立在隧道底部的岩石
+	ObstacleStalactite                     // 挂在隧道顶部的钟乳石
+	ObstacleMine                           // 漂浮在隧道中的水雷
+
+	NumObstacleKinds = iota // 障碍物类型数量
+)
+
+const (
+	obstacleGrace       = 300 // 开局多少距离内不生成障碍物
+	obstacleMinInterval = 150 // 障碍物之间的最小距离
+	obstacleMaxInterval = 400 // 障碍物之间的最大距离
+	obstacleClearance   = PlayerHeight + 6
+)
+
+// ObstacleSpec 障碍物类型的参数
+type ObstacleSpec struct {
+	Name   string
+	W, H   int // 尺寸，与精灵一致
+	Weight int // 生成权重
+}
+
+var obstacleSpecs = [NumObstacleKinds]ObstacleSpec{
+	ObstacleRock:       {Name: "rock", W: 6, H: 4, Weight: 4},
+	ObstacleStalactite: {Name: "stalactite", W: 4, H: 7, Weight: 4},
+	ObstacleMine:       {Name: "mine", W: 5, H: 5, Weight: 2},
+}
+
+// Spec 返回障碍物类型的参数
+func (k ObstacleKind) Spec() ObstacleSpec {
+	if k < 0 || k >= NumObstacleKinds {
+		return ObstacleSpec{}
+	}
+	return obstacleSpecs[k]
+}
+
+// String 返回障碍物类型名
+func (k ObstacleKind) String() string {
+	if name := k.Spec().Name; name != "" {
+		return name
+	}
+	return fmt.Sprintf("obstacle(%d)", int(k))
+}
+
+// ParseObstacleKind 解析障碍物类型名
+func ParseObstacleKind(s string) (ObstacleKind, error) {
+	for k, spec := range obstacleSpecs {
+		if spec.Name == s {
+			return ObstacleKind(k), nil
+		}
+	}
+	return ObstacleRock, fmt.Errorf("unknown obstacle: %q", s)
+}
+
+// MarshalText 以类型名编码
+func (k ObstacleKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText 从类型名解码
+func (k *ObstacleKind) UnmarshalText(b []byte) error {
+	kind, err := ParseObstacleKind(string(b))
+	if err != nil {
+		return err
+	}
+	*k = kind
+	return nil
+}
+
+// Obstacle 隧道中的障碍物，碰到即撞毁，可被炸弹摧毁
+type Obstacle struct {
+	X, Y float64
+	W, H int
+	Kind ObstacleKind
+}
+
+// newObstacle 在指定位置创建障碍物
+func newObstacle(kind ObstacleKind, x, y float64) Obstacle {
+	spec := kind.Spec()
+	return Obstacle{X: x, Y: y, W: spec.W, H: spec.H, Kind: kind}
+}
+
+// ObstacleSpawner 可选接口，生成器实现后可以在隧道中放置障碍物。
+// 每帧在 Next 之后调用，col 为本帧生成的隧道段。
+type ObstacleSpawner interface {
+	Obstacles(distance int, col Column, rng *rand.Rand) []Obstacle
+}
+
+// obstacleSchedule 随机生成障碍物的节奏，可嵌入各生成器
+type obstacleSchedule struct {
+	next int // 下一个障碍物的生成距离
+}
+
+// spawn 到达生成距离时在隧道段 col 中放置一个随机障碍物，隧道太窄时跳过
+func (o *obstacleSchedule) spawn(distance int, col Column, rng *rand.Rand) []Obstacle {
+	if o.next == 0 {
+		o.next = obstacleGrace
+	}
+	if distance < o.next {
+		return nil
+	}
+	o.next = distance + obstacleMinInterval + rng.Intn(obstacleMaxInterval-obstacleMinInterval)
+
+	kind := randomObstacleKind(rng)
+	spec := kind.Spec()
+	if int(col.Height)-spec.H < obstacleClearance {
+		return nil
+	}
+	var y float64
+	switch kind {
+	case ObstacleRock:
+		y = col.TopY + col.Height - float64(spec.H)
+	case ObstacleStalactite:
+		y = col.TopY
+	default:
+		span := max(int(col.Height)-spec.H-6, 1)
+		y = col.TopY + 3 + float64(rng.Intn(span))
+	}
+	return []Obstacle{newObstacle(kind, ScreenWidth-1, y)}
+}
+
+// randomObstacleKind 按生成权重随机选择障碍物类型
+func randomObstacleKind(rng *rand.Rand) ObstacleKind {
+	total := 0
+	for _, spec := range obstacleSpecs {
+		total += spec.Weight
+	}
+	n := rng.Intn(total)
+	for k, spec := range obstacleSpecs {
+		if n < spec.Weight {
+			return ObstacleKind(k)
+		}
+		n -= spec.Weight
+	}
+	return ObstacleRock
+}
@@ -0,0 +1,117 @@
+package sim
+
+import "math"
+
+// PowerUp 限时生效的能力，挂在玩家身上
+type PowerUp int
+
+const (
+	PowerShield PowerUp = iota // 护盾：抵挡一次撞击
+	PowerMagnet                // 磁铁：把附近的道具吸向潜艇
+	PowerSlowMo                // 慢动作：隧道移动速度减半
+
+	NumPowerUps = iota // 能力数量
+)
+
+const (
+	shieldGraceTicks = 30  // 护盾抵挡撞击后无敌的帧数，用于离开墙壁
+	magnetRange      = 40  // 磁铁吸引道具的范围
+	magnetPull       = 1.5 // 磁铁每帧把道具拉近的像素
+)
+
+// PowerUpSpec 能力的参数
+type PowerUpSpec struct {
+	Name  string
+	Ticks int             // 持续帧数
+	Kind  CollectibleKind // 获得该能力的道具
+}
+
+var powerUpSpecs = [NumPowerUps]PowerUpSpec{
+	PowerShield: {Name: "shield", Ticks: 180, Kind: KindShield},
+	PowerMagnet: {Name: "magnet", Ticks: 300, Kind: KindMagnet},
+	PowerSlowMo: {Name: "slowmo", Ticks: 240, Kind: KindTimeSlow},
+}
+
+// Spec 返回能力的参数
+func (p PowerUp) Spec() PowerUpSpec {
+	if p < 0 || p >= NumPowerUps {
+		return PowerUpSpec{}
+	}
+	return powerUpSpecs[p]
+}
+
+// String 返回能力名
+func (p PowerUp) String() string {
+	return p.Spec().Name
+}
+
+// powerUpFor 返回道具对应的能力
+func powerUpFor(kind CollectibleKind) (PowerUp, bool) {
+	for p, spec := range powerUpSpecs {
+		if spec.Kind == kind {
+			return PowerUp(p), true
+		}
+	}
+	return 0, false
+}
+
+// Active 返回能力是否生效中
+func (p Player) Active(power PowerUp) bool {
+	return p.PowerUps[power] > 0
+}
+
+// ActivePowerUps 返回生效中的能力
+func (p Player) ActivePowerUps() []PowerUp {
+	var active []PowerUp
+	for power := range p.PowerUps {
+		if p.PowerUps[power] > 0 {
+			active = append(active, PowerUp(power))
+		}
+	}
+	return active
+}
+
+// grantPowerUp 获得能力，已生效时重新计时
+func (s *Simulation) grantPowerUp(power PowerUp) {
+	s.player.PowerUps[power] = power.Spec().Ticks
+}
+
+// tickPowerUps 每帧递减能力的剩余时间
+func (s *Simulation) tickPowerUps() {
+	for p := range s.player.PowerUps {
+		if s.player.PowerUps[p] > 0 {
+			s.player.PowerUps[p]--
+		}
+	}
+}
+
+// absorbHit 用护盾抵挡一次撞击，返回是否抵挡成功
+func (s *Simulation) absorbHit() bool {
+	if !s.rules.PowerUps || !s.player.Active(PowerShield) {
+		return false
+	}
+	s.player.PowerUps[PowerShield] = 0
+	s.invulnTimer = shieldGraceTicks
+	s.emit(EventShieldHit, s.player.X, s.player.Y)
+	return true
+}
+
+// attractCollectibles 磁铁生效时把范围内的道具拉向潜艇中心
+func (s *Simulation) attractCollectibles() {
+	if !s.player.Active(PowerMagnet) {
+		return
+	}
+	px := s.player.X + PlayerWidth/2
+	py := s.player.Y + PlayerHeight/2
+	for _, c := range s.collectibles {
+		dx := px - (c.X + float64(c.W)/2)
+		dy := py - (c.Y + float64(c.H)/2)
+		d := math.Hypot(dx, dy)
+		if d == 0 || d > magnetRange {
+			continue
+		}
+		step := math.Min(magnetPull, d)
+		c.X += dx / d * step
+		c.Y += dy / d * step
+	}
+}
@@ -0,0 +1,211 @@
+package sim
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RulesVersion 模拟规则版本。
+// 任何会改变同一输入下模拟结果的修改都必须递增该值，旧版本的回放将无法播放。
+//
+//	1: 原版规则
+//	2: 隧道生成器独立后道具上限由 5 改为 maxCollectibles，生成位置的随机数总是消耗
+//	3: 无尽模式在胜利距离之前与经典模式的隧道相同，之后才收窄变陡
+//	4: 多条命模式的检查点保存随机数状态，复活后随机生成的内容与第一次经过时相同
+//	5: 逐像素碰撞判定成为默认，省略碰撞方式的规则不再视为矩形判定
+//	6: 原版生成器恢复道具上限 5 和道具已满时不消耗生成位置随机数的行为，新的上限只用于其他生成器
+//	7: 能力道具只随能力规则生成，不再依赖道具规则
+//	8: 护盾只在启用能力规则时生效，去掉未启用时的限时无敌
+//	9: 无尽模式的隧道坡度不再有上限
+const RulesVersion = 9
+
+// replayFormatVersion 回放文件格式版本
+const replayFormatVersion = 1
+
+// maxReplayFrames 回放允许的最大帧数，防止损坏的文件占用过多内存
+const maxReplayFrames = 1 << 22
+
+// ErrRulesVersion 回放的规则版本与当前模拟不一致
+var ErrRulesVersion = errors.New("replay was recorded with different rules")
+
+// Replay 一局游戏的输入记录，配合种子和规则版本即可逐帧复现
+type Replay struct {
+	RulesVersion int
+	Seed         int64
+	Rules        Rules
+	Score        int // 录制结束时的分数
+	Distance     int // 录制结束时的距离
+	Frames       []Input
+}
+
+// NewReplay 为指定种子和规则创建空的回放记录
+func NewReplay(seed int64, rules Rules) *Replay {
+	return &Replay{
+		RulesVersion: RulesVersion,
+		Seed:         seed,
+		Rules:        rules,
+	}
+}
+
+// Record 追加一帧输入
+func (r *Replay) Record(in Input) {
+	r.Frames = append(r.Frames, in)
+}
+
+// Finish 记录对局结果
+func (r *Replay) Finish(s *Simulation) {
+	r.Score = s.Score()
+	r.Distance = s.Distance()
+}
+
+// Check 检查回放能否在当前规则下播放
+func (r *Replay) Check() error {
+	if r.RulesVersion != RulesVersion {
+		return fmt.Errorf("%w: recorded %d, current %d", ErrRulesVersion, r.RulesVersion, RulesVersion)
+	}
+	return r.Rules.Validate()
+}
+
+// NewSimulation 创建与回放对应的新模拟
+func (r *Replay) NewSimulation() *Simulation {
+	return New(r.Seed, r.Rules)
+}
+
+// Run 在无渲染环境下完整播放回放，返回结束时的模拟状态
+func (r *Replay) Run() (*Simulation, error) {
+	if err := r.Check(); err != nil {
+		return nil, err
+	}
+	s := r.NewSimulation()
+	for _, in := range r.Frames {
+		if s.Status() != StatusRunning {
+			break
+		}
+		s.Step(in)
+	}
+	return s, nil
+}
+
+// replayJSON 回放文件的 JSON 结构
+type replayJSON struct {
+	Version      int    `json:"version"`
+	RulesVersion int    `json:"rules"`
+	Seed         int64  `json:"seed"`
+	Rules        Rules  `json:"ruleset"`
+	Score        int    `json:"score"`
+	Distance     int    `json:"distance"`
+	Frames       string `json:"frames"` // 游程编码后再 base64 编码的输入序列
+}
+
+// MarshalJSON 将回放编码为紧凑的 JSON
+func (r *Replay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(replayJSON{
+		Version:      replayFormatVersion,
+		RulesVersion: r.RulesVersion,
+		Seed:         r.Seed,
+		Rules:        r.Rules,
+		Score:        r.Score,
+		Distance:     r.Distance,
+		Frames:       base64.StdEncoding.EncodeToString(encodeFrames(r.Frames)),
+	})
+}
+
+// UnmarshalJSON 从 JSON 解码回放
+func (r *Replay) UnmarshalJSON(data []byte) error {
+	var raw replayJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Version != replayFormatVersion {
+		return fmt.Errorf("unsupported replay format version: %d", raw.Version)
+	}
+	if raw.Rules.Difficulty.Name == "" {
+		// 旧版回放没有记录难度，均为原版难度
+		raw.Rules.Difficulty = Normal
+	}
+	b, err := base64.StdEncoding.DecodeString(raw.Frames)
+	if err != nil {
+		return fmt.Errorf("invalid replay frames: %w", err)
+	}
+	frames, err := decodeFrames(b)
+	if err != nil {
+		return err
+	}
+	*r = Replay{
+		RulesVersion: raw.RulesVersion,
+		Seed:         raw.Seed,
+		Rules:        raw.Rules,
+		Score:        raw.Score,
+		Distance:     raw.Distance,
+		Frames:       frames,
+	}
+	return nil
+}
+
+// WriteReplay 将回放写入 w
+func WriteReplay(w io.Writer, r *Replay) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// ReadReplay 从 rd 读取回放
+func ReadReplay(rd io.Reader) (*Replay, error) {
+	r := &Replay{}
+	if err := json.NewDecoder(rd).Decode(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// inputBits 将输入压缩为位标志
+func inputBits(in Input) byte {
+	var b byte
+	if in.Up {
+		b |= 1
+	}
+	if in.Bomb {
+		b |= 2
+	}
+	return b
+}
+
+// inputFromBits 从位标志还原输入
+func inputFromBits(b byte) Input {
+	return Input{Up: b&1 != 0, Bomb: b&2 != 0}
+}
+
+// encodeFrames 游程编码：每段为 1 字节输入位标志加 uvarint 重复次数
+func encodeFrames(frames []Input) []byte {
+	var out []byte
+	for i := 0; i < len(frames); {
+		bits := inputBits(frames[i])
+		n := 1
+		for i+n < len(frames) && inputBits(frames[i+n]) == bits {
+			n++
+		}
+		out = append(out, bits)
+		out = binary.AppendUvarint(out, uint64(n))
+		i += n
+	}
+	return out
+}
+
+// decodeFrames 解码游程编码的输入序列
+func decodeFrames(b []byte) ([]Input, error) {
+	var frames []Input
+	for len(b) > 0 {
+		in := inputFromBits(b[0])
+		n, size := binary.Uvarint(b[1:])
+		if size <= 0 || n > uint64(maxReplayFrames-len(frames)) {
+			return nil, errors.New("invalid replay frames: bad run length")
+		}
+		for ; n > 0; n-- {
+			frames = append(frames, in)
+		}
+		b = b[1+size:]
+	}
+	return frames, nil
+}
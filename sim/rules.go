@@ -0,0 +1,238 @@
+package sim
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Mode 游戏模式
+type Mode int
+
+const (
+	ModeClassic Mode = iota // 经典模式：到达 4000 距离获胜
+	ModeEndless             // 无尽模式：隧道不断变窄变陡，直到撞毁
+
+	NumModes = iota // 模式数量
+)
+
+const (
+	endlessHeightFactor    = 0.6  // 无尽模式隧道最小高度相对难度下限的比例
+	endlessSteepenDistance = 4000 // 无尽模式超过胜利距离后，每隔这么远隧道每帧升降多 1 像素
+)
+
+// String 返回模式名
+func (m Mode) String() string {
+	switch m {
+	case ModeClassic:
+		return "classic"
+	case ModeEndless:
+		return "endless"
+	default:
+		return fmt.Sprintf("mode(%d)", int(m))
+	}
+}
+
+// ParseMode 解析模式名，空字符串视为经典模式
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "", "classic":
+		return ModeClassic, nil
+	case "endless":
+		return ModeEndless, nil
+	default:
+		return ModeClassic, fmt.Errorf("unknown game mode: %q", s)
+	}
+}
+
+// MarshalText 以模式名编码
+func (m Mode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText 从模式名解码
+func (m *Mode) UnmarshalText(b []byte) error {
+	mode, err := ParseMode(string(b))
+	if err != nil {
+		return err
+	}
+	*m = mode
+	return nil
+}
+
+// Rules 一局游戏的规则配置，回放中会一并记录
+type Rules struct {
+	Mode       Mode        `json:"mode,omitempty"`
+	Difficulty Difficulty  `json:"difficulty"`
+	Course     *Course     `json:"course,omitempty"`    // 非空时按关卡生成隧道和金币
+	Generator  string      `json:"generator,omitempty"` // 隧道生成器名字，空为原版
+	Collision  Collision   `json:"collision,omitempty"` // 碰撞判定方式，默认逐像素判定
+	Masks      *Masks      `json:"masks,omitempty"`     // 逐像素判定使用的精灵掩码
+	Items      bool        `json:"items,omitempty"`     // 是否生成钻石、红鱼和炸弹补给
+	Bombs      *BombRules  `json:"bombs,omitempty"`     // 炸弹规则，nil 为原版
+	Obstacles  bool        `json:"obstacles,omitempty"` // 是否在随机隧道中生成障碍物
+	Enemies    bool        `json:"enemies,omitempty"`   // 是否在随机隧道中生成敌人
+	Blast      int         `json:"blast,omitempty"`     // 炸弹的爆炸半径，0 为原版的全屏清除
+	Scoring    bool        `json:"scoring,omitempty"`   // 是否启用连击倍率和擦墙奖励
+	Lives      *LivesRules `json:"lives,omitempty"`     // 多条命与检查点规则，nil 为原版的一条命
+	PowerUps   bool        `json:"powerups,omitempty"`  // 是否生成护盾、时间减缓和磁铁，护盾抵挡一次撞击
+}
+
+// DefaultRules 返回默认规则：逐像素碰撞判定，其余与原版游戏相同
+func DefaultRules() Rules {
+	return Rules{Mode: ModeClassic, Difficulty: Normal}
+}
+
+// Equal 检查两套规则是否相同，关卡按内容比较
+func (r Rules) Equal(o Rules) bool {
+	return r.Mode == o.Mode && r.Difficulty == o.Difficulty && r.generator() == o.generator() &&
+		r.Collision == o.Collision && reflect.DeepEqual(r.Masks, o.Masks) && r.Items == o.Items &&
+		reflect.DeepEqual(r.Bombs, o.Bombs) && r.Obstacles == o.Obstacles && r.Enemies == o.Enemies &&
+		r.Blast == o.Blast && r.Scoring == o.Scoring && reflect.DeepEqual(r.Lives, o.Lives) &&
+		r.PowerUps == o.PowerUps && equalCourse(r.Course, o.Course)
+}
+
+// Hash 返回规则的短哈希，由规范化后的 JSON 计算，Equal 的两套规则哈希相同
+func (r Rules) Hash() string {
+	r.Generator = r.generator()
+	r.Difficulty = r.difficulty()
+	data, _ := json.Marshal(r) // 规则只包含可编码的字段，不会失败
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Validate 检查规则能否用于创建模拟
+func (r Rules) Validate() error {
+	// 难度参数随回放文件传入，为零的间隔会在生成隧道时除零
+	if err := r.difficulty().Validate(); err != nil {
+		return err
+	}
+	if r.Course != nil {
+		if err := r.Course.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Bombs != nil {
+		if err := r.Bombs.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Lives != nil {
+		if err := r.Lives.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Blast < 0 || r.Blast > ScreenWidth {
+		return fmt.Errorf("blast radius must be between 0 and %d", ScreenWidth)
+	}
+	return checkGenerator(r.Generator)
+}
+
+// bombRules 返回本局的炸弹规则
+func (r Rules) bombRules() BombRules {
+	if r.Bombs == nil {
+		return originalBombRules
+	}
+	return *r.Bombs
+}
+
+// playerMask 返回逐像素判定时玩家的掩码，未设置时为原版矩形
+func (r Rules) playerMask() *Mask {
+	if r.Masks != nil && r.Masks.Player != nil {
+		return r.Masks.Player
+	}
+	return NewRectMask(PlayerWidth, PlayerHeight)
+}
+
+// collectibleMask 返回逐像素判定时道具的掩码，未设置时为整张图的矩形
+func (r Rules) collectibleMask(kind CollectibleKind) *Mask {
+	if r.Masks != nil {
+		if kind == KindCoin && r.Masks.Coin != nil {
+			return r.Masks.Coin
+		}
+		if m := r.Masks.Items[kind]; m != nil {
+			return m
+		}
+	}
+	spec := kind.Spec()
+	return NewRectMask(spec.W, spec.H)
+}
+
+// obstacleMask 返回逐像素判定时障碍物的掩码，未设置时为整张图的矩形
+func (r Rules) obstacleMask(kind ObstacleKind) *Mask {
+	if r.Masks != nil {
+		if m := r.Masks.Obstacles[kind]; m != nil {
+			return m
+		}
+	}
+	spec := kind.Spec()
+	return NewRectMask(spec.W, spec.H)
+}
+
+// spawnsObstacles 检查本局是否生成障碍物，关卡中的障碍物总是生成
+func (r Rules) spawnsObstacles() bool {
+	return r.Obstacles || r.Course != nil
+}
+
+// enemyMask 返回逐像素判定时敌人的掩码，未设置时为整张图的矩形
+func (r Rules) enemyMask(kind EnemyKind) *Mask {
+	if r.Masks != nil {
+		if m := r.Masks.Enemies[kind]; m != nil {
+			return m
+		}
+	}
+	spec := kind.Spec()
+	return NewRectMask(spec.W, spec.H)
+}
+
+// spawnsEnemies 检查本局是否生成敌人，关卡中的敌人总是生成
+func (r Rules) spawnsEnemies() bool {
+	return r.Enemies || r.Course != nil
+}
+
+// generator 返回隧道生成器名字，未设置时为原版
+func (r Rules) generator() string {
+	if r.Generator == "" {
+		return OriginalGenerator
+	}
+	return r.Generator
+}
+
+// difficulty 返回本局的难度，未设置时（如旧版回放）使用 Normal
+func (r Rules) difficulty() Difficulty {
+	if r.Difficulty.Name == "" {
+		return Normal
+	}
+	return r.Difficulty
+}
+
+// minTunnelHeight 返回隧道收窄的下限，无尽模式超过胜利距离后才低于经典模式
+func (r Rules) minTunnelHeight(distance int) float64 {
+	if r.Mode == ModeEndless && distance > WinDistance {
+		return r.difficulty().MinTunnelHeight * endlessHeightFactor
+	}
+	return r.difficulty().MinTunnelHeight
+}
+
+// slopeStep 返回隧道每帧升降的像素数，无尽模式超过胜利距离后随距离不断变陡
+func (r Rules) slopeStep(distance int) float64 {
+	if r.Mode != ModeEndless || distance <= WinDistance {
+		return 1
+	}
+	return 1 + float64(distance-WinDistance)/endlessSteepenDistance
+}
+
+// hasWinDistance 检查该模式是否有胜利距离，关卡总有终点
+func (r Rules) hasWinDistance() bool {
+	return r.Course != nil || r.Mode != ModeEndless
+}
+
+// winDistance 返回胜利距离
+func (r Rules) winDistance() int {
+	if r.Course != nil {
+		return r.Course.WinDistance
+	}
+	return WinDistance
+}
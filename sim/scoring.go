@@ -0,0 +1,89 @@
+package sim
+
+import "math"
+
+const (
+	comboStep     = 3   // 连续吃到多少个道具倍率加一
+	comboWindow   = 170 // 多少帧内没有吃到道具时倍率降一级，略长于金币的最大间隔
+	maxMultiplier = 5   // 连击倍率上限
+	nearMissGap   = 3   // 与墙壁距离在多少像素内算擦墙
+	nearMissTicks = 20  // 连续擦墙多少帧得一次奖励
+	nearMissScore = 1   // 每次擦墙奖励的基础分，乘以连击倍率
+)
+
+// ScoreBreakdown 一局分数的组成
+type ScoreBreakdown struct {
+	Distance  int // 距离得分
+	Items     int // 金币和道具的基础分
+	Combo     int // 连击倍率带来的额外分
+	NearMiss  int // 擦墙奖励
+	BombBonus int // 胜利时剩余炸弹的奖励
+}
+
+// Total 返回各项分数之和
+func (b ScoreBreakdown) Total() int {
+	return b.Distance + b.Items + b.Combo + b.NearMiss + b.BombBonus
+}
+
+// award 加分并计入分数组成中的一项
+func (s *Simulation) award(part *int, points int) {
+	*part += points
+	s.score += points
+}
+
+// Breakdown 返回当前分数的组成
+func (s *Simulation) Breakdown() ScoreBreakdown {
+	return s.breakdown
+}
+
+// Multiplier 返回当前的连击倍率，未启用连击规则时为 1
+func (s *Simulation) Multiplier() int {
+	return max(s.comboLevel, 1)
+}
+
+// chainCombo 吃到有分数的道具时延长连击，每连续 comboStep 个倍率加一
+func (s *Simulation) chainCombo() {
+	s.comboChain++
+	if s.comboChain%comboStep == 0 && s.Multiplier() < maxMultiplier {
+		s.comboLevel = s.Multiplier() + 1
+	}
+	s.comboTimer = comboWindow
+}
+
+// updateScoring 连击倍率随时间衰减，并检查擦墙奖励
+func (s *Simulation) updateScoring() {
+	if s.comboTimer > 0 {
+		s.comboTimer--
+		if s.comboTimer == 0 {
+			s.comboChain = 0
+			s.comboLevel = s.Multiplier() - 1
+			if s.comboLevel > 1 {
+				s.comboTimer = comboWindow
+			}
+		}
+	}
+
+	gap := s.wallGap()
+	if s.invulnTimer > 0 || s.player.Active(PowerShield) || gap < 0 || gap > nearMissGap {
+		s.nearMissTimer = 0
+		return
+	}
+	s.nearMissTimer++
+	if s.nearMissTimer%nearMissTicks == 0 {
+		s.award(&s.breakdown.NearMiss, nearMissScore*s.Multiplier())
+		s.emit(EventNearMiss, s.player.X, s.player.Y)
+	}
+}
+
+// wallGap 返回玩家碰撞矩形与上下墙壁之间较小的距离
+func (s *Simulation) wallGap() float64 {
+	r := s.playerRect()
+	top, bottom := 0.0, float64(ScreenHeight)
+	for _, t := range s.tunnels {
+		if int(t.X) < r.Max.X && r.Min.X < int(t.X+t.Width) {
+			top = math.Max(top, t.TopY)
+			bottom = math.Min(bottom, t.TopY+t.Height)
+		}
+	}
+	return math.Min(s.player.Y-top, bottom-s.player.Y-PlayerHeight)
+}
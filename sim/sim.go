@@ -0,0 +1,751 @@
+// Package sim 实现与渲染无关的游戏模拟核心。
+//
+// 本包不依赖 ebiten，可以在没有显示设备的环境（如 CI）中批量运行。
+// 每次调用 Step 推进一帧，通过 Snapshot 读取只读的状态快照。
+package sim
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/rand"
+)
+
+const (
+	ScreenWidth  = 160
+	ScreenHeight = 80
+
+	PlayerWidth  = 8 // 玩家碰撞矩形宽度
+	PlayerHeight = 4 // 玩家碰撞矩形高度
+	CoinWidth    = 3 // 金币宽度
+	CoinHeight   = 5 // 金币高度
+
+	WinDistance = 4000 // 胜利距离
+
+	playerStartX    = ScreenWidth / 4 // 玩家的X坐标
+	maxCollectibles = 32              // 屏幕内道具数量上限
+)
+
+// 屏幕上下边界之外的区域，逐像素判定时视为墙壁
+var (
+	aboveScreen = image.Rect(math.MinInt16, math.MinInt16, math.MaxInt16, 0)
+	belowScreen = image.Rect(math.MinInt16, ScreenHeight, math.MaxInt16, math.MaxInt16)
+)
+
+// Status 模拟运行状态
+type Status int
+
+const (
+	StatusRunning Status = iota // 进行中
+	StatusCrashed               // 撞毁
+	StatusWon                   // 胜利
+)
+
+// Input 一帧的游戏输入
+type Input struct {
+	Up   bool // 是否按住上升
+	Bomb bool // 是否触发炸弹
+}
+
+type Player struct {
+	X, Y     float64
+	VY       float64          // Vertical velocity
+	PowerUps [NumPowerUps]int // 各能力剩余的帧数
+}
+
+type Tunnel struct {
+	X      float64
+	TopY   float64
+	Height float64
+	Width  float64
+}
+
+type Collectible struct {
+	X, Y float64
+	W, H int
+	Kind CollectibleKind
+}
+
+// EventKind 模拟事件类型
+type EventKind int
+
+const (
+	EventCoin      EventKind = iota // 吃到金币
+	EventBomb                       // 释放炸弹
+	EventCrash                      // 撞毁
+	EventWin                        // 胜利
+	EventItem                       // 吃到金币以外的道具
+	EventBlast                      // 发射的炸弹爆炸
+	EventNearMiss                   // 擦墙飞行得到奖励
+	EventLifeLost                   // 撞毁但还有命，即将在检查点复活
+	EventRespawn                    // 在检查点复活
+	EventShieldHit                  // 护盾抵挡了一次撞击
+)
+
+// CrashCause 撞毁原因
+type CrashCause int
+
+const (
+	CrashNone     CrashCause = iota // 没有撞毁
+	CrashBoundary                   // 飞出屏幕上下边界
+	CrashWall                       // 撞到隧道墙壁
+	CrashObstacle                   // 撞到障碍物
+	CrashEnemy                      // 撞到敌人
+
+	NumCrashCauses = iota // 撞毁原因数量
+)
+
+var crashCauseNames = [NumCrashCauses]string{
+	CrashNone:     "none",
+	CrashBoundary: "boundary",
+	CrashWall:     "wall",
+	CrashObstacle: "obstacle",
+	CrashEnemy:    "enemy",
+}
+
+// String 返回撞毁原因名
+func (c CrashCause) String() string {
+	if c >= 0 && c < NumCrashCauses {
+		return crashCauseNames[c]
+	}
+	return fmt.Sprintf("crash(%d)", int(c))
+}
+
+// Event 模拟过程中产生的事件，供上层播放音效、显示消息等
+type Event struct {
+	Kind  EventKind
+	X, Y  float64         // 事件发生位置
+	Item  CollectibleKind // EventItem 吃到的道具类型
+	Cause CrashCause      // EventCrash、EventLifeLost 的撞毁原因
+}
+
+// Snapshot 模拟状态的只读快照
+type Snapshot struct {
+	Tick         int
+	Distance     int
+	Score        int
+	Bombs        int
+	BombsUsed    int
+	Bombing      bool
+	Invulnerable int  // 无敌剩余帧数（复活或护盾抵挡撞击后）
+	Multiplier   int  // 连击倍率
+	NearMiss     bool // 是否正在擦墙飞行
+	Lives        int  // 剩余命数
+	Respawn      int  // 复活前剩余的帧数，0 表示不在复活中
+	Status       Status
+	Player       Player
+	Tunnels      []Tunnel
+	Collectibles []Collectible
+	Obstacles    []Obstacle
+	Enemies      []Enemy
+	Projectile   *Projectile // 飞行中的炸弹，没有时为 nil
+	Blast        *Blast      // 正在显示的爆炸效果，没有时为 nil
+}
+
+// Simulation 一局游戏的模拟状态
+type Simulation struct {
+	rng        *rand.Rand
+	seed       int64
+	rules      Rules
+	difficulty Difficulty
+	generator  TunnelGenerator
+	playerMask *Mask // 逐像素判定时的玩家掩码，矩形判定时为 nil
+	itemMasks  [NumCollectibleKinds]*Mask
+	rockMasks  [NumObstacleKinds]*Mask
+	enemyMasks [NumEnemyKinds]*Mask
+
+	player       Player
+	tunnels      []*Tunnel
+	collectibles []*Collectible
+	obstacles    []*Obstacle
+	enemies      []*Enemy
+	tick         int
+	distance     int
+	score        int
+	bombs        int
+	bombRules    BombRules
+	bombsUsed    int
+	coins        int // 本局吃到的金币数，复活时不回退
+	isBombing    bool
+	bombTimer    int
+	projectile   *Projectile
+	blast        *Blast
+	invulnTimer  int
+	status       Status
+
+	breakdown     ScoreBreakdown
+	comboChain    int // 连续吃到的道具数
+	comboLevel    int // 连击倍率，0 与 1 相同
+	comboTimer    int // 倍率降一级前剩余的帧数
+	nearMissTimer int // 连续擦墙的帧数
+
+	lives        int
+	respawnTimer int
+	checkpoint   *checkpoint
+
+	events []Event
+}
+
+// New 使用指定种子和规则创建一局新的模拟
+func New(seed int64, rules Rules) *Simulation {
+	s := &Simulation{
+		rng:        rand.New(rand.NewSource(seed)),
+		seed:       seed,
+		rules:      rules,
+		difficulty: rules.difficulty(),
+		bombRules:  rules.bombRules(),
+	}
+	s.bombs = s.bombRules.Start
+	s.generator = newGenerator(rules, s.rng)
+	if rules.Collision == CollisionMask {
+		s.playerMask = rules.playerMask()
+		for k := range s.itemMasks {
+			s.itemMasks[k] = rules.collectibleMask(CollectibleKind(k))
+		}
+		for k := range s.rockMasks {
+			s.rockMasks[k] = rules.obstacleMask(ObstacleKind(k))
+		}
+		for k := range s.enemyMasks {
+			s.enemyMasks[k] = rules.enemyMask(EnemyKind(k))
+		}
+	}
+	start := s.generator.Start()
+	s.player = Player{
+		X: playerStartX,
+		Y: start.TopY + start.Height/2,
+	}
+	if p, ok := s.generator.(Prefiller); ok {
+		for _, t := range p.Prefill() {
+			s.tunnels = append(s.tunnels, &t)
+		}
+	}
+	s.lives = 1
+	if rules.Lives != nil {
+		s.lives = rules.Lives.Count
+		s.saveCheckpoint()
+	}
+	return s
+}
+
+// Seed 返回本局使用的随机种子
+func (s *Simulation) Seed() int64 {
+	return s.seed
+}
+
+// Rules 返回本局的规则
+func (s *Simulation) Rules() Rules {
+	return s.rules
+}
+
+// Status 返回当前运行状态
+func (s *Simulation) Status() Status {
+	return s.status
+}
+
+// Score 返回当前分数
+func (s *Simulation) Score() int {
+	return s.score
+}
+
+// Tick 返回已推进的帧数
+func (s *Simulation) Tick() int {
+	return s.tick
+}
+
+// Distance 返回当前距离
+func (s *Simulation) Distance() int {
+	return s.distance
+}
+
+// Player 返回玩家状态
+func (s *Simulation) Player() Player {
+	return s.player
+}
+
+// BombsUsed 返回本局已使用的炸弹数
+func (s *Simulation) BombsUsed() int {
+	return s.bombsUsed
+}
+
+// Coins 返回本局吃到的金币数
+func (s *Simulation) Coins() int {
+	return s.coins
+}
+
+// BombBonus 返回胜利时剩余炸弹获得的奖励分
+func (s *Simulation) BombBonus() int {
+	return s.breakdown.BombBonus
+}
+
+// Bombing 返回是否处于炸弹爆炸状态
+func (s *Simulation) Bombing() bool {
+	return s.isBombing
+}
+
+// Snapshot 返回当前状态的只读快照
+func (s *Simulation) Snapshot() Snapshot {
+	snap := Snapshot{
+		Tick:         s.tick,
+		Distance:     s.distance,
+		Score:        s.score,
+		Bombs:        s.bombs,
+		BombsUsed:    s.bombsUsed,
+		Bombing:      s.isBombing,
+		Invulnerable: s.invulnTimer,
+		Multiplier:   s.Multiplier(),
+		NearMiss:     s.nearMissTimer > 0,
+		Lives:        s.lives,
+		Respawn:      s.respawnTimer,
+		Status:       s.status,
+		Player:       s.player,
+		Tunnels:      make([]Tunnel, len(s.tunnels)),
+		Collectibles: make([]Collectible, len(s.collectibles)),
+		Obstacles:    make([]Obstacle, len(s.obstacles)),
+		Enemies:      make([]Enemy, len(s.enemies)),
+	}
+	for i, t := range s.tunnels {
+		snap.Tunnels[i] = *t
+	}
+	for i, c := range s.collectibles {
+		snap.Collectibles[i] = *c
+	}
+	for i, o := range s.obstacles {
+		snap.Obstacles[i] = *o
+	}
+	for i, e := range s.enemies {
+		snap.Enemies[i] = *e
+	}
+	if s.projectile != nil {
+		p := *s.projectile
+		snap.Projectile = &p
+	}
+	if s.blast != nil {
+		b := *s.blast
+		snap.Blast = &b
+	}
+	return snap
+}
+
+// Step 根据输入推进一帧，返回本帧产生的事件。
+// 返回的切片在下一次调用 Step 前有效。
+func (s *Simulation) Step(in Input) []Event {
+	s.events = s.events[:0]
+	if s.status != StatusRunning {
+		return s.events
+	}
+	s.tick++
+
+	// 撞毁后等待复活
+	if s.respawnTimer > 0 {
+		s.respawnTimer--
+		if s.respawnTimer == 0 {
+			s.respawn()
+		}
+		return s.events
+	}
+
+	// 1. 炸弹状态递减与爆炸效果
+	if s.isBombing {
+		s.updateBombState()
+		return s.events
+	}
+
+	// 2. 炸弹触发
+	s.tryTriggerBomb(in.Bomb)
+	if s.isBombing {
+		return s.events
+	}
+
+	// 3-6. 世界推进，时间减缓时隔帧推进
+	s.tickPowerUps()
+	if !s.player.Active(PowerSlowMo) || s.tick%2 == 0 {
+		s.advanceWorld()
+		if s.status == StatusWon {
+			return s.events
+		}
+	}
+
+	// 7. 玩家操作与物理
+	s.updatePlayerVelocity(in.Up)
+	s.clampPlayerVelocity()
+	s.updatePlayerPosition()
+
+	// 8. 碰撞检测，护盾期间不会撞毁
+	if s.invulnTimer > 0 {
+		s.invulnTimer--
+		s.keepPlayerOnScreen()
+	} else if cause := s.hitCause(); cause != CrashNone {
+		if !s.absorbHit() {
+			if !s.loseLife(cause) {
+				s.status = StatusCrashed
+				s.emitCrash(EventCrash, cause)
+			}
+			return s.events
+		}
+	}
+	s.checkPlayerCollectibleCollision()
+	if s.rules.Scoring {
+		s.updateScoring()
+	}
+
+	return s.events
+}
+
+// advanceWorld 推进距离并生成、移动隧道和道具
+func (s *Simulation) advanceWorld() {
+	// 3. 距离、分数、胜利判定
+	s.updateDistanceAndScore()
+	if s.status == StatusWon {
+		return
+	}
+
+	// 4. 由生成器产生新的隧道段和道具
+	if l, ok := s.generator.(CollectibleLimiter); ok {
+		l.SetOnScreen(len(s.collectibles))
+	}
+	col, spawns := s.generator.Next(s.distance, s.rng)
+
+	// 5. 隧道生成与移动
+	s.spawnTunnel(col)
+	s.moveTunnels()
+	s.removeOffscreenTunnels()
+
+	// 6. 道具生成与移动
+	s.spawnCollectibles(spawns)
+	s.moveCollectibles()
+	s.removeOffscreenCollectibles()
+
+	// 障碍物生成与移动
+	if o, ok := s.generator.(ObstacleSpawner); ok && s.rules.spawnsObstacles() {
+		for _, ob := range o.Obstacles(s.distance, col, s.rng) {
+			s.obstacles = append(s.obstacles, &ob)
+		}
+	}
+	s.moveObstacles()
+
+	// 敌人生成与按脚本移动
+	if e, ok := s.generator.(EnemySpawner); ok && s.rules.spawnsEnemies() {
+		for _, en := range e.Enemies(s.distance, col, s.rng) {
+			s.enemies = append(s.enemies, &en)
+		}
+	}
+	s.moveEnemies()
+
+	// 飞行中的炸弹
+	s.updateProjectile()
+
+	// 检查点
+	if s.rules.Lives != nil && s.distance%s.rules.Lives.Checkpoint == 0 {
+		s.saveCheckpoint()
+	}
+}
+
+// emit 记录一个事件
+func (s *Simulation) emit(kind EventKind, x, y float64) {
+	s.events = append(s.events, Event{Kind: kind, X: x, Y: y})
+}
+
+// emitCrash 在玩家位置产生带撞毁原因的事件
+func (s *Simulation) emitCrash(kind EventKind, cause CrashCause) {
+	s.events = append(s.events, Event{Kind: kind, X: s.player.X, Y: s.player.Y, Cause: cause})
+}
+
+// updateBombState 处理炸弹状态递减、爆炸效果（清空隧道、道具、障碍物和敌人）
+func (s *Simulation) updateBombState() {
+	s.bombTimer--
+	if s.bombTimer <= 0 {
+		s.isBombing = false
+		s.tunnels = []*Tunnel{}
+		s.collectibles = []*Collectible{}
+		s.obstacles = nil
+		s.enemies = nil
+	}
+}
+
+// tryTriggerBomb 检查是否满足触发炸弹条件，若满足则消耗炸弹。
+// 原版规则下进入全屏爆炸状态，否则发射炸弹，同一时间只能有一个炸弹在飞行。
+func (s *Simulation) tryTriggerBomb(pressed bool) {
+	if !pressed || s.bombs <= 0 || s.isBombing || s.projectile != nil {
+		return
+	}
+	s.bombs--
+	s.bombsUsed++
+	s.emit(EventBomb, s.player.X, s.player.Y)
+	if s.rules.Blast > 0 {
+		s.launchProjectile()
+		return
+	}
+	s.isBombing = true
+	s.bombTimer = 15
+}
+
+// updateDistanceAndScore 距离递增、分数递增，胜利判定
+func (s *Simulation) updateDistanceAndScore() {
+	s.distance++
+	if s.distance%40 == 0 {
+		s.award(&s.breakdown.Distance, 1)
+	}
+	if s.rules.hasWinDistance() && s.distance >= s.rules.winDistance() {
+		s.award(&s.breakdown.BombBonus, s.bombs*s.bombRules.UnusedBonus)
+		s.status = StatusWon
+		s.emit(EventWin, s.player.X, s.player.Y)
+	}
+}
+
+// spawnTunnel 在屏幕右侧生成新的隧道段
+func (s *Simulation) spawnTunnel(col Column) {
+	s.tunnels = append(s.tunnels, &Tunnel{
+		X:      159,
+		TopY:   col.TopY,
+		Height: col.Height,
+		Width:  10,
+	})
+}
+
+// moveTunnels 所有隧道段左移
+func (s *Simulation) moveTunnels() {
+	for _, t := range s.tunnels {
+		t.X -= 1.0
+	}
+}
+
+// removeOffscreenTunnels 移除超出屏幕的隧道段
+func (s *Simulation) removeOffscreenTunnels() {
+	remaining := s.tunnels[:0]
+	for _, t := range s.tunnels {
+		if t.X+t.Width > 0 {
+			remaining = append(remaining, t)
+		}
+	}
+	s.tunnels = remaining
+}
+
+// spawnCollectibles 加入生成器产生的道具，超过上限的丢弃
+func (s *Simulation) spawnCollectibles(spawns []Collectible) {
+	for _, c := range spawns {
+		if len(s.collectibles) >= maxCollectibles {
+			break
+		}
+		s.collectibles = append(s.collectibles, &c)
+	}
+}
+
+// moveCollectibles 所有道具左移，磁铁生效时被吸向潜艇
+func (s *Simulation) moveCollectibles() {
+	for _, c := range s.collectibles {
+		c.X -= 1.0
+	}
+	s.attractCollectibles()
+}
+
+// removeOffscreenCollectibles 移除超出屏幕的道具
+func (s *Simulation) removeOffscreenCollectibles() {
+	remaining := s.collectibles[:0]
+	for _, c := range s.collectibles {
+		if c.X+float64(c.W) > 0 {
+			remaining = append(remaining, c)
+		}
+	}
+	s.collectibles = remaining
+}
+
+// moveObstacles 所有障碍物左移，移除超出屏幕的障碍物
+func (s *Simulation) moveObstacles() {
+	remaining := s.obstacles[:0]
+	for _, o := range s.obstacles {
+		o.X -= 1.0
+		if o.X+float64(o.W) > 0 {
+			remaining = append(remaining, o)
+		}
+	}
+	s.obstacles = remaining
+}
+
+// moveEnemies 按移动脚本移动敌人并限制在隧道内，移除超出屏幕的敌人
+func (s *Simulation) moveEnemies() {
+	remaining := s.enemies[:0]
+	for _, e := range s.enemies {
+		e.Age++
+		e.X -= 1.0
+		enemyScripts[e.Pattern](e, s.player)
+		s.keepEnemyInTunnel(e)
+		if e.X+float64(e.W) > 0 {
+			remaining = append(remaining, e)
+		}
+	}
+	s.enemies = remaining
+}
+
+// keepEnemyInTunnel 把敌人限制在所在位置的隧道上下壁之间
+func (s *Simulation) keepEnemyInTunnel(e *Enemy) {
+	top, bottom := 0.0, float64(ScreenHeight)
+	for _, t := range s.tunnels {
+		if t.X < e.X+float64(e.W) && e.X < t.X+t.Width {
+			top = math.Max(top, t.TopY)
+			bottom = math.Min(bottom, t.TopY+t.Height)
+		}
+	}
+	if bottom-top < float64(e.H) {
+		return
+	}
+	e.Y = math.Max(top, math.Min(e.Y, bottom-float64(e.H)))
+}
+
+// updatePlayerVelocity 根据输入更新玩家速度
+func (s *Simulation) updatePlayerVelocity(isUp bool) {
+	if isUp {
+		s.player.VY -= s.difficulty.Thrust
+	} else {
+		s.player.VY += s.difficulty.Gravity
+	}
+}
+
+// clampPlayerVelocity 限制玩家速度在合理范围
+func (s *Simulation) clampPlayerVelocity() {
+	maxVY := s.difficulty.MaxVelocity
+	if s.player.VY > maxVY {
+		s.player.VY = maxVY
+	}
+	if s.player.VY < -maxVY {
+		s.player.VY = -maxVY
+	}
+}
+
+// updatePlayerPosition 根据速度更新玩家位置
+func (s *Simulation) updatePlayerPosition() {
+	s.player.Y += s.player.VY
+}
+
+// playerRect 返回玩家的碰撞矩形
+func (s *Simulation) playerRect() image.Rectangle {
+	return image.Rect(int(s.player.X), int(s.player.Y), int(s.player.X)+PlayerWidth, int(s.player.Y)+PlayerHeight)
+}
+
+// playerOverlapsRect 检查玩家是否与矩形重叠，逐像素判定时只计不透明像素
+func (s *Simulation) playerOverlapsRect(r image.Rectangle) bool {
+	if s.playerMask != nil {
+		return s.playerMask.OverlapsRect(image.Pt(int(s.player.X), int(s.player.Y)), r)
+	}
+	return s.playerRect().Overlaps(r)
+}
+
+// keepPlayerOnScreen 护盾期间把玩家限制在屏幕内
+func (s *Simulation) keepPlayerOnScreen() {
+	if s.player.Y < 0 {
+		s.player.Y, s.player.VY = 0, 0
+	}
+	if s.player.Y > ScreenHeight-PlayerHeight {
+		s.player.Y, s.player.VY = ScreenHeight-PlayerHeight, 0
+	}
+}
+
+// hitCause 返回玩家本帧撞到的东西，没有撞到时返回 CrashNone
+func (s *Simulation) hitCause() CrashCause {
+	switch {
+	case s.checkPlayerBoundaryCollision():
+		return CrashBoundary
+	case s.checkPlayerTunnelCollision():
+		return CrashWall
+	case s.checkPlayerObstacleCollision():
+		return CrashObstacle
+	case s.checkPlayerEnemyCollision():
+		return CrashEnemy
+	}
+	return CrashNone
+}
+
+// checkPlayerBoundaryCollision 检查玩家是否撞到上下边界。
+// 逐像素判定时 Y 向下取整而不是截断，略高于上边界时与矩形判定一样算作撞毁
+func (s *Simulation) checkPlayerBoundaryCollision() bool {
+	if s.playerMask != nil {
+		at := image.Pt(int(s.player.X), int(math.Floor(s.player.Y)))
+		return s.playerMask.OverlapsRect(at, aboveScreen) || s.playerMask.OverlapsRect(at, belowScreen)
+	}
+	return s.player.Y < 0 || int(s.player.Y)+PlayerHeight > ScreenHeight
+}
+
+// checkPlayerTunnelCollision 检查玩家是否撞到隧道
+func (s *Simulation) checkPlayerTunnelCollision() bool {
+	for _, t := range s.tunnels {
+		topRect := image.Rect(int(t.X), 0, int(t.X+t.Width), int(t.TopY))
+		bottomRect := image.Rect(int(t.X), int(t.TopY+t.Height), int(t.X+t.Width), ScreenHeight)
+		if s.playerOverlapsRect(topRect) || s.playerOverlapsRect(bottomRect) {
+			return true
+		}
+	}
+	return false
+}
+
+// playerHits 检查玩家是否碰到位于 (x, y)、大小为 w×h 的物体，逐像素判定时使用物体的掩码 m
+func (s *Simulation) playerHits(m *Mask, x, y float64, w, h int) bool {
+	if s.playerMask != nil {
+		return s.playerMask.Overlaps(image.Pt(int(s.player.X), int(s.player.Y)), m, image.Pt(int(x), int(y)))
+	}
+	return s.playerRect().Overlaps(image.Rect(int(x), int(y), int(x)+w, int(y)+h))
+}
+
+// checkPlayerObstacleCollision 检查玩家是否撞到障碍物
+func (s *Simulation) checkPlayerObstacleCollision() bool {
+	for _, o := range s.obstacles {
+		if s.playerHits(s.rockMasks[o.Kind], o.X, o.Y, o.W, o.H) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPlayerEnemyCollision 检查玩家是否撞到敌人
+func (s *Simulation) checkPlayerEnemyCollision() bool {
+	for _, e := range s.enemies {
+		if s.playerHits(s.enemyMasks[e.Kind], e.X, e.Y, e.W, e.H) {
+			return true
+		}
+	}
+	return false
+}
+
+// playerTouchesCollectible 检查玩家是否碰到道具
+func (s *Simulation) playerTouchesCollectible(c *Collectible) bool {
+	return s.playerHits(s.itemMasks[c.Kind], c.X, c.Y, c.W, c.H)
+}
+
+// collect 吃到道具：加分并产生道具效果
+func (s *Simulation) collect(c *Collectible) {
+	score := c.Kind.Spec().Score
+	s.award(&s.breakdown.Items, score)
+	if s.rules.Scoring && score > 0 {
+		s.award(&s.breakdown.Combo, score*(s.Multiplier()-1))
+		s.chainCombo()
+	}
+	switch c.Kind {
+	case KindCoin:
+		s.coins++
+		s.emit(EventCoin, c.X, c.Y)
+		return
+	case KindBombRefill:
+		if s.bombs < s.bombRules.Cap {
+			s.bombs++
+		}
+	default:
+		// 关卡可能放置能力道具，未启用能力规则时只计分
+		if p, ok := powerUpFor(c.Kind); ok && s.rules.PowerUps {
+			s.grantPowerUp(p)
+		}
+	}
+	s.events = append(s.events, Event{Kind: EventItem, X: c.X, Y: c.Y, Item: c.Kind})
+}
+
+// checkPlayerCollectibleCollision 检查玩家是否吃到道具，处理分数
+func (s *Simulation) checkPlayerCollectibleCollision() (collected bool) {
+	remaining := s.collectibles[:0]
+	for _, c := range s.collectibles {
+		if s.playerTouchesCollectible(c) {
+			s.collect(c)
+			collected = true
+			continue
+		}
+		remaining = append(remaining, c)
+	}
+	s.collectibles = remaining
+	return
+}
@@ -0,0 +1,169 @@
+package sim
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// testRules 打开所有可选规则，让测试覆盖尽量多的随机数消耗
+func testRules() Rules {
+	rules := DefaultRules()
+	rules.Mode = ModeEndless
+	rules.Items = true
+	rules.Obstacles = true
+	rules.Enemies = true
+	rules.Scoring = true
+	rules.PowerUps = true
+	rules.Blast = DefaultBlastRadius
+	bombs := DefaultBombRules()
+	rules.Bombs = &bombs
+	lives := DefaultLivesRules()
+	rules.Lives = &lives
+	return rules
+}
+
+// scriptedInput 测试用的输入脚本：跟随玩家前方隧道的中线，每隔一段时间放一次炸弹。
+// 输入只由模拟状态和帧数决定，同样的种子总是得到同样的输入序列
+func scriptedInput(s *Simulation, frame int) Input {
+	snap := s.Snapshot()
+	p := snap.Player
+	target := ScreenHeight / 2.0
+	for _, t := range snap.Tunnels {
+		if t.X >= p.X+PlayerWidth {
+			target = t.TopY + t.Height/2
+			break
+		}
+	}
+	return Input{Up: p.Y+PlayerHeight/2+p.VY*4 > target, Bomb: frame%300 == 0}
+}
+
+// runScript 按输入脚本运行模拟直到结束或到达 maxFrames，返回模拟和全部事件
+func runScript(seed int64, rules Rules, maxFrames int) (*Simulation, []Event) {
+	s := New(seed, rules)
+	var events []Event
+	for frame := 0; frame < maxFrames && s.Status() == StatusRunning; frame++ {
+		events = append(events, s.Step(scriptedInput(s, frame))...)
+	}
+	return s, events
+}
+
+func TestDeterminism(t *testing.T) {
+	for _, seed := range []int64{1, 42, 12345678} {
+		a, eventsA := runScript(seed, testRules(), 5000)
+		b, eventsB := runScript(seed, testRules(), 5000)
+		if a.Score() != b.Score() || a.Distance() != b.Distance() || a.Status() != b.Status() {
+			t.Errorf("seed %d: run 1 ended with score %d distance %d, run 2 with score %d distance %d",
+				seed, a.Score(), a.Distance(), b.Score(), b.Distance())
+		}
+		if !reflect.DeepEqual(eventsA, eventsB) {
+			t.Errorf("seed %d: runs produced different events", seed)
+		}
+		if !reflect.DeepEqual(a.Snapshot(), b.Snapshot()) {
+			t.Errorf("seed %d: runs ended in different states", seed)
+		}
+	}
+}
+
+func TestReplayRoundTrip(t *testing.T) {
+	rules := testRules()
+	s := New(7, rules)
+	r := NewReplay(7, rules)
+	for frame := 0; frame < 5000 && s.Status() == StatusRunning; frame++ {
+		in := scriptedInput(s, frame)
+		r.Record(in)
+		s.Step(in)
+	}
+	r.Finish(s)
+
+	var buf bytes.Buffer
+	if err := WriteReplay(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ReadReplay(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Seed != r.Seed || !decoded.Rules.Equal(r.Rules) || !reflect.DeepEqual(decoded.Frames, r.Frames) {
+		t.Fatal("decoded replay differs from the recorded one")
+	}
+	played, err := decoded.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if played.Score() != decoded.Score || played.Distance() != decoded.Distance {
+		t.Errorf("replay ended with score %d distance %d, recorded score %d distance %d",
+			played.Score(), played.Distance(), decoded.Score, decoded.Distance)
+	}
+	if decoded.Score != s.Score() || decoded.Distance != s.Distance() {
+		t.Errorf("decoded result score %d distance %d, want %d %d", decoded.Score, decoded.Distance, s.Score(), s.Distance())
+	}
+}
+
+func TestBoundaryCollisionModesAgree(t *testing.T) {
+	// 未设置掩码时玩家的掩码是整个矩形，两种判定在屏幕边缘应当一致
+	crashed := func(c Collision, y float64) bool {
+		rules := DefaultRules()
+		rules.Collision = c
+		s := New(1, rules)
+		s.player.Y = y
+		return s.checkPlayerBoundaryCollision()
+	}
+	for _, y := range []float64{-1.5, -0.5, 0, 0.5, 76, 76.5, 77, 77.5} {
+		rect, mask := crashed(CollisionRect, y), crashed(CollisionMask, y)
+		if rect != mask {
+			t.Errorf("y = %v: rect collision crashed %v, mask collision crashed %v", y, rect, mask)
+		}
+	}
+}
+
+func TestRespawnRestoresRandomSpawns(t *testing.T) {
+	rules := DefaultRules()
+	rules.Items = true
+	rules.Obstacles = true
+	rules.Lives = &LivesRules{Count: 2, Checkpoint: 500}
+	s := New(5, rules)
+
+	// 保持无敌飞到检查点之后，记下这时的隧道和障碍物
+	advanceTo := func(distance int) Snapshot {
+		for s.Distance() < distance {
+			s.invulnTimer = 1 << 30
+			s.Step(Input{})
+		}
+		return s.Snapshot()
+	}
+	first := advanceTo(700)
+
+	// 飞出屏幕撞毁，等待在距离 500 的检查点复活
+	s.invulnTimer = 0
+	s.player.Y = -ScreenHeight
+	for s.Lives() == 2 || s.respawnTimer > 0 {
+		s.Step(Input{})
+	}
+	if s.Distance() != 500 {
+		t.Fatalf("respawned at distance %d, want 500", s.Distance())
+	}
+	second := advanceTo(700)
+	if !reflect.DeepEqual(first.Tunnels, second.Tunnels) || !reflect.DeepEqual(first.Obstacles, second.Obstacles) {
+		t.Error("tunnel and obstacles after respawning differ from the first pass")
+	}
+}
+
+func TestOriginalCollectibleLimit(t *testing.T) {
+	// 原版道具已满时跳过生成，只消耗下一个生成距离的随机数
+	c := coinSpawner{rules: DefaultRules(), nextItem: 32, limit: originalMaxCollectibles, onScreen: originalMaxCollectibles}
+	rng, want := rand.New(rand.NewSource(1)), rand.New(rand.NewSource(1))
+	if spawns := c.spawn(32, Column{TopY: 15, Height: 50}, rng); len(spawns) != 0 {
+		t.Fatalf("spawned %d collectibles with a full screen", len(spawns))
+	}
+	want.Intn(5)
+	if rng.Int63() != want.Int63() {
+		t.Error("skipped spawn consumed a different number of random values")
+	}
+
+	c.onScreen = originalMaxCollectibles - 1
+	if spawns := c.spawn(32+c.nextItem, Column{TopY: 15, Height: 50}, rng); len(spawns) != 1 {
+		t.Errorf("spawned %d collectibles below the limit, want 1", len(spawns))
+	}
+}
@@ -0,0 +1,160 @@
+package rush
+
+import (
+	"fmt"
+	"log"
+
+	"rush/sim"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Stats 玩家的累计统计，跨局保存，中途退出的对局不计入
+type Stats struct {
+	GamesPlayed   int            `json:"games_played"`
+	Wins          int            `json:"wins"`
+	TotalDistance int            `json:"total_distance"`
+	BestDistance  int            `json:"best_distance"`
+	Coins         int            `json:"coins"`
+	BombsUsed     int            `json:"bombs_used"`
+	Crashes       map[string]int `json:"crashes"`   // 按撞毁原因统计的撞毁次数，包括还有命时的撞毁
+	PlayTime      int            `json:"play_time"` // 已结束对局的累计游戏时间，单位为帧
+}
+
+// crashLabels 统计界面上各撞毁原因的显示名
+var crashLabels = [sim.NumCrashCauses]string{
+	sim.CrashBoundary: "OFF SCREEN",
+	sim.CrashWall:     "HIT WALL",
+	sim.CrashObstacle: "HIT ROCK",
+	sim.CrashEnemy:    "HIT ENEMY",
+}
+
+// loadStats 读取累计统计
+func (g *Game) loadStats() error {
+	stats, err := g.statsStorage.Load()
+	if err != nil {
+		return err
+	}
+	g.stats = stats
+	return nil
+}
+
+// saveStats 保存累计统计，失败时只记录日志，读取失败过的存档不会被覆盖
+func (g *Game) saveStats() {
+	if g.statsFailed {
+		return
+	}
+	if err := g.statsStorage.Save(g.stats); err != nil {
+		log.Printf("Failed to save stats: %v", err)
+	}
+}
+
+// countsForStats 检查当前对局是否计入累计统计，播放回放和试玩编辑器关卡时不统计
+func (g *Game) countsForStats() bool {
+	return !g.isPlayingBack() && !g.isTestPlaying()
+}
+
+// trackStatsEvent 根据模拟事件累计本局统计，一局结束时计入累计统计并保存
+func (g *Game) trackStatsEvent(e sim.Event) {
+	if !g.countsForStats() {
+		return
+	}
+	switch e.Kind {
+	case sim.EventCoin:
+		g.runStats.Coins++
+	case sim.EventBomb:
+		g.runStats.BombsUsed++
+	case sim.EventLifeLost:
+		g.runStats.countCrash(e.Cause)
+	case sim.EventCrash:
+		g.runStats.countCrash(e.Cause)
+		g.finishStatsRun()
+	case sim.EventWin:
+		g.runStats.Wins++
+		g.finishStatsRun()
+	}
+}
+
+// countCrash 按原因累计撞毁次数
+func (s *Stats) countCrash(cause sim.CrashCause) {
+	if s.Crashes == nil {
+		s.Crashes = make(map[string]int)
+	}
+	s.Crashes[cause.String()]++
+}
+
+// add 把一局的统计计入累计统计
+func (s *Stats) add(run Stats) {
+	s.GamesPlayed += run.GamesPlayed
+	s.Wins += run.Wins
+	s.TotalDistance += run.TotalDistance
+	s.BestDistance = max(s.BestDistance, run.BestDistance)
+	s.Coins += run.Coins
+	s.BombsUsed += run.BombsUsed
+	s.PlayTime += run.PlayTime
+	for cause, n := range run.Crashes {
+		if s.Crashes == nil {
+			s.Crashes = make(map[string]int)
+		}
+		s.Crashes[cause] += n
+	}
+}
+
+// finishStatsRun 一局结束时把本局统计计入累计统计并保存。
+// 中途退出的对局不计入任何统计
+func (g *Game) finishStatsRun() {
+	g.runStats.GamesPlayed = 1
+	g.runStats.TotalDistance = g.sim.Distance()
+	g.runStats.BestDistance = g.sim.Distance()
+	g.runStats.PlayTime = g.sim.Tick()
+	g.stats.add(g.runStats)
+	g.runStats = Stats{}
+	g.saveStats()
+}
+
+// formatPlayTime 把帧数格式化为 时:分:秒
+func formatPlayTime(frames int) string {
+	seconds := frames / 60
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// statsMenuItems 返回统计界面的各行，借用文字菜单以便滚动
+func (g *Game) statsMenuItems() []menuItem {
+	s := g.stats
+	rows := []struct {
+		label string
+		value string
+	}{
+		{"GAMES", fmt.Sprint(s.GamesPlayed)},
+		{"WINS", fmt.Sprint(s.Wins)},
+		{"DISTANCE", fmt.Sprint(s.TotalDistance)},
+		{"BEST", fmt.Sprint(s.BestDistance)},
+		{"COINS", fmt.Sprint(s.Coins)},
+		{"BOMBS", fmt.Sprint(s.BombsUsed)},
+		{"TIME", formatPlayTime(s.PlayTime)},
+	}
+	for cause, label := range crashLabels {
+		if label != "" {
+			rows = append(rows, struct{ label, value string }{label, fmt.Sprint(s.Crashes[sim.CrashCause(cause).String()])})
+		}
+	}
+
+	items := make([]menuItem, 0, len(rows)+1)
+	for _, row := range rows {
+		items = append(items, menuItem{fmt.Sprintf("%-11s%8s", row.label, row.value), func() {}})
+	}
+	return append(items, menuItem{"Back", func() { g.state = StateMore }})
+}
+
+// updateStats 处理统计界面输入
+func (g *Game) updateStats() error {
+	if g.updateMenuList(g.statsMenuItems(), &g.statsChoice) {
+		g.state = StateMore
+	}
+	return nil
+}
+
+// drawStats 绘制累计统计
+func (g *Game) drawStats(screen *ebiten.Image) {
+	drawMenuList(screen, "STATS", g.statsMenuItems(), g.statsChoice)
+}
@@ -0,0 +1,14 @@
+package rush
+
+// StatsStorage 累计统计存储接口，与排行榜保存在同一位置。
+// Load 失败时游戏不再调用 Save，以免覆盖原来的存档
+type StatsStorage interface {
+	Save(stats Stats) error
+	Load() (Stats, error)
+}
+
+// NewStatsStorage 返回当前平台的统计存储实现
+func NewStatsStorage() StatsStorage {
+	// 具体实现由各平台的 build tag 文件提供
+	return newStatsStorage()
+}
@@ -0,0 +1,23 @@
+//go:build android
+
+package rush
+
+import "path/filepath"
+
+type androidStatsStorage struct {
+	filePath string
+}
+
+func newStatsStorage() StatsStorage {
+	return &androidStatsStorage{
+		filePath: filepath.Join(androidDataDir(), "stats.json"),
+	}
+}
+
+func (s *androidStatsStorage) Save(stats Stats) error {
+	return saveJSONFile(s.filePath, stats)
+}
+
+func (s *androidStatsStorage) Load() (Stats, error) {
+	return loadJSONFile[Stats](s.filePath)
+}
@@ -0,0 +1,25 @@
+//go:build !android && !js
+
+package rush
+
+import "path/filepath"
+
+type desktopStatsStorage struct{}
+
+func newStatsStorage() StatsStorage {
+	return &desktopStatsStorage{}
+}
+
+// statsFile 返回统计文件路径，位于存档目录中
+func (s *desktopStatsStorage) statsFile() string {
+	return filepath.Join(desktopDataDir(), "stats.json")
+}
+
+func (s *desktopStatsStorage) Save(stats Stats) error {
+	return saveJSONFile(s.statsFile(), stats)
+}
+
+func (s *desktopStatsStorage) Load() (Stats, error) {
+	// 文件不存在时从零开始统计
+	return loadJSONFile[Stats](s.statsFile())
+}
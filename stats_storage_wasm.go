@@ -0,0 +1,34 @@
+//go:build js && wasm
+
+package rush
+
+import (
+	"encoding/json"
+	"syscall/js"
+)
+
+type wasmStatsStorage struct{}
+
+func newStatsStorage() StatsStorage {
+	return &wasmStatsStorage{}
+}
+
+const wasmStatsKey = "rush_stats"
+
+func (s *wasmStatsStorage) Save(stats Stats) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	return localStorageSet(wasmStatsKey, string(data))
+}
+
+func (s *wasmStatsStorage) Load() (Stats, error) {
+	var loaded Stats
+	item := js.Global().Get("localStorage").Call("getItem", wasmStatsKey)
+	if item.IsNull() || item.IsUndefined() {
+		return loaded, nil
+	}
+	err := json.Unmarshal([]byte(item.String()), &loaded)
+	return loaded, err
+}